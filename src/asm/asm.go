@@ -0,0 +1,686 @@
+// Assembles RGBDS style source into bytes, mainly for writing CPU tests and
+// small test ROMs. Supports labels (including .local ones), EQU constants,
+// SECTION, DB, DW, DS and the usual expression operators along with HIGH()
+// and LOW(). Memory operands can be written with either [] or ().
+package asm
+
+import (
+	"cpu"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"types"
+)
+
+type Error struct {
+	Line int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// A contiguous block of assembled bytes
+type Section struct {
+	Name string
+	Type string
+	Address types.Word
+	Data []byte
+}
+
+type Program struct {
+	Sections []*Section
+	// Every label, with local labels under their full Global.local name
+	Labels map[string]types.Word
+}
+
+// Anything the assembled bytes can be written to, e.g. memory.Memory
+type Writer interface {
+	Set(address types.Word, value byte) error
+}
+
+// Writes every section to memory at its address
+func (p *Program) Load(mem Writer) error {
+	for _, section := range p.Sections {
+		for i, value := range section.Data {
+			if err := mem.Set(section.Address + types.Word(i), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Flattens the sections falling within [start, start+size) into one image,
+// e.g. a ROM. Gaps are zero
+func (p *Program) Image(start types.Word, size int) []byte {
+	image := make([]byte, size)
+	for _, section := range p.Sections {
+		for i, value := range section.Data {
+			offset := int(section.Address) + i - int(start)
+			if offset >= 0 && offset < size {
+				image[offset] = value
+			}
+		}
+	}
+	return image
+}
+
+// Start of each section type, used for sections without a fixed address
+var sectionBases = map[string]int{
+	"ROM0": 0x0000,
+	"ROMX": 0x4000,
+	"VRAM": 0x8000,
+	"SRAM": 0xA000,
+	"WRAM0": 0xC000,
+	"WRAMX": 0xD000,
+	"OAM": 0xFE00,
+	"HRAM": 0xFF80,
+}
+
+// Opcode bytes for every legal instruction keyed by its spec mnemonic, e.g.
+// "LD B,d8" or "BIT 7,H"
+var instructionCodes = make(map[string][]byte)
+
+func init() {
+	for code, inst := range cpu.MainInstructions {
+		if !inst.Illegal() && code != 0xCB {
+			instructionCodes[inst.Mnemonic] = []byte{byte(code)}
+		}
+	}
+	for code, inst := range cpu.CBInstructions {
+		instructionCodes[inst.Mnemonic] = []byte{0xCB, byte(code)}
+	}
+}
+
+// Operands that appear literally in the spec mnemonics, keyed by their
+// upper case, space free source form
+var literalOperands = map[string]string{
+	"A": "A", "B": "B", "C": "C", "D": "D", "E": "E", "H": "H", "L": "L",
+	"AF": "AF", "BC": "BC", "DE": "DE", "HL": "HL", "SP": "SP",
+	"NZ": "NZ", "Z": "Z", "NC": "NC",
+	"(HL)": "(HL)", "(BC)": "(BC)", "(DE)": "(DE)", "(C)": "(C)", "($FF00+C)": "(C)",
+	"(HL+)": "(HL+)", "(HLI)": "(HL+)", "(HL-)": "(HL-)", "(HLD)": "(HL-)",
+}
+
+// Operand placeholders from the spec and how many bytes each takes
+var placeholderSizes = map[string]int{
+	"d8": 1, "a8": 1, "r8": 1, "d16": 2, "a16": 2,
+}
+
+var (
+	labelPattern = regexp.MustCompile(`^\s*([A-Za-z_.][\w.#@]*)(::?)`)
+	localLabelPattern = regexp.MustCompile(`^(\.[\w#@]+)(\s|$)`)
+	// NAME EQU expr or DEF NAME EQU expr
+	equPattern = regexp.MustCompile(`(?i)^\s*(?:DEF\s+)?([A-Za-z_][\w.#@]*)\s+EQU\s+(.*)$`)
+	sectionPattern = regexp.MustCompile(`(?i)^"([^"]*)"\s*,\s*(\w+)\s*(?:\[(.*?)\])?`)
+)
+
+// An assembled line, sized in the first pass and emitted in the second once
+// every label is known
+type statement struct {
+	line int
+	section *Section
+	address int
+	global string
+	size int
+	emit func(ctx *context) ([]byte, error)
+}
+
+type assembler struct {
+	labels map[string]int
+	equs map[string]string
+	resolving map[string]bool
+	statements []*statement
+	sections []*Section
+	section *Section
+	sectionNext map[string]int
+	address int
+	global string
+	line int
+}
+
+// Evaluation context for a single statement
+type context struct {
+	a *assembler
+	address int
+	global string
+}
+
+func (c *context) currentAddress() int {
+	return c.address
+}
+
+func (c *context) symbol(name string) (int, error) {
+	if strings.HasPrefix(name, ".") {
+		name = c.global + name
+	}
+	if value, exists := c.a.labels[name]; exists {
+		return value, nil
+	}
+	expr, exists := c.a.equs[name]
+	if !exists {
+		return 0, fmt.Errorf("Undefined symbol %s", name)
+	}
+	if c.a.resolving[name] {
+		return 0, fmt.Errorf("Symbol %s is defined in terms of itself", name)
+	}
+	c.a.resolving[name] = true
+	defer delete(c.a.resolving, name)
+	return evaluate(expr, c)
+}
+
+func (c *context) evaluate(expr string) (int, error) {
+	return evaluate(expr, c)
+}
+
+// Assembles source into a program. Code before the first SECTION goes into
+// an unnamed ROM0 section at $0000
+func Assemble(source string) (*Program, error) {
+	a := &assembler{
+		labels: make(map[string]int),
+		equs: make(map[string]string),
+		resolving: make(map[string]bool),
+		sectionNext: make(map[string]int),
+	}
+	for index, line := range strings.Split(source, "\n") {
+		a.line = index + 1
+		if err := a.parseLine(line); err != nil {
+			return nil, &Error{Line: a.line, Message: err.Error()}
+		}
+	}
+	if err := a.checkOverlaps(); err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range a.statements {
+		ctx := &context{a: a, address: stmt.address, global: stmt.global}
+		data, err := stmt.emit(ctx)
+		if err != nil {
+			return nil, &Error{Line: stmt.line, Message: err.Error()}
+		}
+		stmt.section.Data = append(stmt.section.Data, data...)
+	}
+
+	program := &Program{Sections: a.sections, Labels: make(map[string]types.Word)}
+	for name, value := range a.labels {
+		program.Labels[name] = types.Word(value)
+	}
+	return program, nil
+}
+
+// Like Assemble but panics on error, for use in tests
+func MustAssemble(source string) *Program {
+	program, err := Assemble(source)
+	if err != nil {
+		panic(err)
+	}
+	return program
+}
+
+func (a *assembler) context() *context {
+	return &context{a: a, address: a.address, global: a.global}
+}
+
+func (a *assembler) parseLine(line string) error {
+	line = stripComment(line)
+
+	if match := labelPattern.FindStringSubmatch(line); match != nil {
+		if err := a.defineLabel(match[1]); err != nil {
+			return err
+		}
+		line = line[len(match[0]):]
+	} else if match := localLabelPattern.FindStringSubmatch(line); match != nil {
+		if err := a.defineLabel(match[1]); err != nil {
+			return err
+		}
+		line = line[len(match[1]):]
+	}
+
+	if match := equPattern.FindStringSubmatch(line); match != nil {
+		return a.defineEqu(match[1], match[2])
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	mnemonic := strings.ToUpper(fields[0])
+	rest := strings.TrimSpace(strings.TrimSpace(line)[len(fields[0]):])
+	operands, err := splitOperands(rest)
+	if err != nil {
+		return err
+	}
+
+	switch mnemonic {
+	case "SECTION":
+		return a.startSection(rest)
+	case "DB":
+		return a.addData(operands, 1)
+	case "DW":
+		return a.addData(operands, 2)
+	case "DS":
+		return a.addSpace(operands)
+	}
+	return a.addInstruction(mnemonic, operands)
+}
+
+// Drops everything after a ; that isn't inside a string
+func stripComment(line string) string {
+	inString := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inString = !inString
+		case r == ';' && !inString:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// Splits on the commas that aren't inside brackets, parentheses or strings
+func splitOperands(text string) ([]string, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	var operands []string
+	depth := 0
+	inString := false
+	start := 0
+	for i, r := range text {
+		switch {
+		case r == '"':
+			inString = !inString
+		case inString:
+		case r == '(' || r == '[':
+			depth++
+		case r == ')' || r == ']':
+			depth--
+		case r == ',' && depth == 0:
+			operands = append(operands, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	if depth != 0 || inString {
+		return nil, fmt.Errorf("Unbalanced brackets or quotes in %q", text)
+	}
+	return append(operands, strings.TrimSpace(text[start:])), nil
+}
+
+func (a *assembler) defineLabel(name string) error {
+	if strings.HasPrefix(name, ".") {
+		if a.global == "" {
+			return fmt.Errorf("Local label %s before any global label", name)
+		}
+		name = a.global + name
+	} else if !strings.Contains(name, ".") {
+		a.global = name
+	}
+	if _, exists := a.labels[name]; exists {
+		return fmt.Errorf("Label %s already defined", name)
+	}
+	if _, exists := a.equs[name]; exists {
+		return fmt.Errorf("Label %s already defined as a constant", name)
+	}
+	a.ensureSection()
+	a.labels[name] = a.address
+	return nil
+}
+
+func (a *assembler) defineEqu(name string, expr string) error {
+	if _, exists := a.labels[name]; exists {
+		return fmt.Errorf("Constant %s already defined as a label", name)
+	}
+	if _, exists := a.equs[name]; exists {
+		return fmt.Errorf("Constant %s already defined", name)
+	}
+	a.equs[name] = strings.TrimSpace(expr)
+	return nil
+}
+
+// SECTION "name", TYPE or SECTION "name", TYPE[address]
+func (a *assembler) startSection(text string) error {
+	match := sectionPattern.FindStringSubmatch(text)
+	if match == nil {
+		return fmt.Errorf("Bad SECTION %q", text)
+	}
+	name, sectionType := match[1], strings.ToUpper(match[2])
+	base, known := sectionBases[sectionType]
+	if !known {
+		return fmt.Errorf("Unknown section type %s", sectionType)
+	}
+	for _, section := range a.sections {
+		if section.Name == name {
+			return fmt.Errorf("Section %q already defined", name)
+		}
+	}
+	a.endSection()
+
+	address, placed := a.sectionNext[sectionType]
+	if !placed {
+		address = base
+	}
+	if match[3] != "" {
+		value, err := a.context().evaluate(match[3])
+		if err != nil {
+			return err
+		}
+		address = value
+	}
+	if address < 0 || address > 0xFFFF {
+		return fmt.Errorf("Section address $%X out of range", address)
+	}
+	a.section = &Section{Name: name, Type: sectionType, Address: types.Word(address)}
+	a.sections = append(a.sections, a.section)
+	a.address = address
+	return nil
+}
+
+// Floating sections of the same type are placed after this one
+func (a *assembler) endSection() {
+	if a.section != nil && a.address > a.sectionNext[a.section.Type] {
+		a.sectionNext[a.section.Type] = a.address
+	}
+}
+
+func (a *assembler) ensureSection() {
+	if a.section == nil {
+		a.section = &Section{Type: "ROM0"}
+		a.sections = append(a.sections, a.section)
+	}
+}
+
+func (a *assembler) checkOverlaps() error {
+	a.endSection()
+	sections := make([]*Section, len(a.sections))
+	copy(sections, a.sections)
+	sort.Slice(sections, func(i, j int) bool {
+		return sections[i].Address < sections[j].Address
+	})
+	for i := 1; i < len(sections); i++ {
+		prev := sections[i-1]
+		if int(prev.Address) + a.sectionSize(prev) > int(sections[i].Address) {
+			return fmt.Errorf("Sections %q and %q overlap", prev.Name, sections[i].Name)
+		}
+	}
+	return nil
+}
+
+func (a *assembler) sectionSize(section *Section) int {
+	size := 0
+	for _, stmt := range a.statements {
+		if stmt.section == section {
+			size += stmt.size
+		}
+	}
+	return size
+}
+
+func (a *assembler) addStatement(size int, emit func(ctx *context) ([]byte, error)) error {
+	a.ensureSection()
+	if a.address + size > 0x10000 {
+		return fmt.Errorf("Code runs past $FFFF")
+	}
+	a.statements = append(a.statements, &statement{
+		line: a.line,
+		section: a.section,
+		address: a.address,
+		global: a.global,
+		size: size,
+		emit: emit,
+	})
+	a.address += size
+	return nil
+}
+
+func encodeValue(value int, size int) ([]byte, error) {
+	if size == 1 {
+		if value < -128 || value > 0xFF {
+			return nil, fmt.Errorf("Value %d doesn't fit in a byte", value)
+		}
+		return []byte{byte(value)}, nil
+	}
+	if value < -32768 || value > 0xFFFF {
+		return nil, fmt.Errorf("Value %d doesn't fit in a word", value)
+	}
+	lsb, msb := types.Word(value).ToBytes()
+	return []byte{lsb, msb}, nil
+}
+
+func (a *assembler) addData(operands []string, width int) error {
+	if len(operands) == 0 {
+		return fmt.Errorf("Expected at least one value")
+	}
+	size := 0
+	for _, operand := range operands {
+		if width == 1 && strings.HasPrefix(operand, "\"") {
+			text, err := strconv.Unquote(operand)
+			if err != nil {
+				return fmt.Errorf("Bad string %s", operand)
+			}
+			size += len(text)
+		} else {
+			size += width
+		}
+	}
+	return a.addStatement(size, func(ctx *context) ([]byte, error) {
+		var data []byte
+		for _, operand := range operands {
+			if width == 1 && strings.HasPrefix(operand, "\"") {
+				text, _ := strconv.Unquote(operand)
+				data = append(data, text...)
+				continue
+			}
+			value, err := ctx.evaluate(operand)
+			if err != nil {
+				return nil, err
+			}
+			encoded, err := encodeValue(value, width)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, encoded...)
+		}
+		return data, nil
+	})
+}
+
+// DS count or DS count, fill
+func (a *assembler) addSpace(operands []string) error {
+	if len(operands) == 0 || len(operands) > 2 {
+		return fmt.Errorf("DS takes a count and an optional fill byte")
+	}
+	count, err := a.context().evaluate(operands[0])
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return fmt.Errorf("Negative DS count %d", count)
+	}
+	return a.addStatement(count, func(ctx *context) ([]byte, error) {
+		fill := 0
+		if len(operands) == 2 {
+			if fill, err = ctx.evaluate(operands[1]); err != nil {
+				return nil, err
+			}
+		}
+		encoded, err := encodeValue(fill, 1)
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, count)
+		for i := range data {
+			data[i] = encoded[0]
+		}
+		return data, nil
+	})
+}
+
+// How an operand could be written in a spec mnemonic, along with the
+// expression to evaluate for placeholders
+type operandForm struct {
+	template string
+	expr string
+}
+
+func operandForms(operand string) []operandForm {
+	if strings.HasPrefix(operand, "[") && strings.HasSuffix(operand, "]") {
+		operand = "(" + operand[1:len(operand)-1] + ")"
+	}
+	key := strings.ToUpper(strings.Join(strings.Fields(operand), ""))
+	if literal, exists := literalOperands[key]; exists {
+		return []operandForm{{template: literal}}
+	}
+	if strings.HasPrefix(key, "SP+") || strings.HasPrefix(key, "SP-") {
+		return []operandForm{{template: "SP+r8", expr: strings.TrimSpace(operand)[2:]}}
+	}
+	if strings.HasPrefix(operand, "(") && strings.HasSuffix(operand, ")") {
+		inner := operand[1:len(operand)-1]
+		return []operandForm{{"(a16)", inner}, {"(a8)", inner}}
+	}
+	return []operandForm{{"d8", operand}, {"d16", operand}, {"a16", operand}, {"r8", operand}}
+}
+
+// Rewrites the shorthand forms RGBDS accepts into the spec's
+func (a *assembler) canonicalOperands(mnemonic string, operands []string) (string, []string, error) {
+	switch mnemonic {
+	case "LDI", "LDD":
+		for i, operand := range operands {
+			if forms := operandForms(operand); forms[0].template == "(HL)" {
+				operands[i] = "(HL" + map[string]string{"LDI": "+", "LDD": "-"}[mnemonic] + ")"
+			}
+		}
+		mnemonic = "LD"
+	case "LDH":
+		for _, operand := range operands {
+			if forms := operandForms(operand); forms[0].template == "(C)" {
+				mnemonic = "LD"
+			}
+		}
+	case "JP":
+		if len(operands) == 1 && strings.EqualFold(operands[0], "HL") {
+			operands[0] = "(HL)"
+		}
+	case "STOP":
+		operands = []string{"0"}
+	case "ADD", "ADC", "SBC":
+		if len(operands) == 1 {
+			operands = append([]string{"A"}, operands...)
+		}
+	case "SUB", "AND", "XOR", "OR", "CP":
+		if len(operands) == 2 && strings.EqualFold(operands[0], "A") {
+			operands = operands[1:]
+		}
+	case "RST", "BIT", "RES", "SET":
+		// The first operand is part of the opcode so must be known now
+		if len(operands) == 0 {
+			return "", nil, fmt.Errorf("%s needs an operand", mnemonic)
+		}
+		value, err := a.context().evaluate(operands[0])
+		if err != nil {
+			return "", nil, err
+		}
+		if mnemonic == "RST" {
+			operands[0] = fmt.Sprintf("%02XH", value)
+		} else {
+			operands[0] = strconv.Itoa(value)
+		}
+	}
+	return mnemonic, operands, nil
+}
+
+func (a *assembler) addInstruction(mnemonic string, operands []string) error {
+	mnemonic, operands, err := a.canonicalOperands(mnemonic, operands)
+	if err != nil {
+		return err
+	}
+	forms := make([][]operandForm, len(operands))
+	for i, operand := range operands {
+		if mnemonic == "RST" || (i == 0 && (mnemonic == "BIT" || mnemonic == "RES" || mnemonic == "SET")) ||
+			(mnemonic == "STOP") {
+			forms[i] = []operandForm{{template: operand}}
+		} else {
+			forms[i] = operandForms(operand)
+		}
+	}
+
+	// Tries every combination of operand forms against the spec
+	var match func(index int, chosen []operandForm) ([]byte, operandForm, bool)
+	match = func(index int, chosen []operandForm) ([]byte, operandForm, bool) {
+		if index == len(forms) {
+			templates := make([]string, len(chosen))
+			var placeholder operandForm
+			for i, form := range chosen {
+				templates[i] = form.template
+				if form.expr != "" {
+					placeholder = form
+				}
+			}
+			key := mnemonic
+			if len(templates) > 0 {
+				key += " " + strings.Join(templates, ",")
+			}
+			code, exists := instructionCodes[key]
+			return code, placeholder, exists
+		}
+		for _, form := range forms[index] {
+			if code, placeholder, ok := match(index + 1, append(chosen, form)); ok {
+				return code, placeholder, true
+			}
+		}
+		return nil, operandForm{}, false
+	}
+	code, placeholder, ok := match(0, nil)
+	if !ok {
+		return fmt.Errorf("Unknown instruction %s %s", mnemonic, strings.Join(operands, ","))
+	}
+
+	operandSize := 0
+	for name, size := range placeholderSizes {
+		if strings.Contains(placeholder.template, name) {
+			operandSize = size
+		}
+	}
+	size := len(code) + operandSize
+	if mnemonic == "STOP" {
+		size = 2
+	}
+	return a.addStatement(size, func(ctx *context) ([]byte, error) {
+		data := append([]byte{}, code...)
+		if mnemonic == "STOP" {
+			return append(data, 0x00), nil
+		}
+		if placeholder.expr == "" {
+			return data, nil
+		}
+		value, err := ctx.evaluate(placeholder.expr)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case placeholder.template == "(a8)":
+			if value >= 0xFF00 && value <= 0xFFFF {
+				value -= 0xFF00
+			}
+			if value < 0 || value > 0xFF {
+				return nil, fmt.Errorf("Address $%X isn't in the high page", value)
+			}
+		case strings.Contains(placeholder.template, "r8"):
+			if mnemonic == "JR" {
+				value -= ctx.address + size
+			}
+			if value < -128 || value > 127 {
+				return nil, fmt.Errorf("Offset %d out of range", value)
+			}
+		}
+		encoded, err := encodeValue(value, operandSize)
+		if err != nil {
+			return nil, err
+		}
+		return append(data, encoded...), nil
+	})
+}
@@ -0,0 +1,173 @@
+package asm
+
+import (
+	"bytes"
+	"disasm"
+	"testing"
+	"types"
+)
+
+func TestAssembleInstructions(t *testing.T) {
+	tests := []struct {
+		source string
+		expected []byte
+	}{
+		{"nop", []byte{0x00}},
+		{"ld b, $3C", []byte{0x06, 0x3C}},
+		{"ld hl, $C000", []byte{0x21, 0x00, 0xC0}},
+		{"ld [hl+], a", []byte{0x22}},
+		{"ld a, (hld)", []byte{0x3A}},
+		{"ldi [hl], a", []byte{0x22}},
+		{"ld [$C000], a", []byte{0xEA, 0x00, 0xC0}},
+		{"ld [$C000], sp", []byte{0x08, 0x00, 0xC0}},
+		{"ldh [$FF44], a", []byte{0xE0, 0x44}},
+		{"ldh a, [$44]", []byte{0xF0, 0x44}},
+		{"ldh [c], a", []byte{0xE2}},
+		{"ld a, [$FF00+c]", []byte{0xF2}},
+		{"ld hl, sp+5", []byte{0xF8, 0x05}},
+		{"ld hl, sp-2", []byte{0xF8, 0xFE}},
+		{"add sp, -1", []byte{0xE8, 0xFF}},
+		{"add a, b", []byte{0x80}},
+		{"add b", []byte{0x80}},
+		{"sub a, c", []byte{0x91}},
+		{"cp $10", []byte{0xFE, 0x10}},
+		{"jp hl", []byte{0xE9}},
+		{"jp nz, $0150", []byte{0xC2, 0x50, 0x01}},
+		{"call c, $1234", []byte{0xDC, 0x34, 0x12}},
+		{"ret nc", []byte{0xD0}},
+		{"rst $38", []byte{0xFF}},
+		{"bit 7, h", []byte{0xCB, 0x7C}},
+		{"set 1 + 2, [hl]", []byte{0xCB, 0xDE}},
+		{"stop", []byte{0x10, 0x00}},
+		{"push af", []byte{0xF5}},
+		{"db 1, \"Hi\", -1", []byte{0x01, 'H', 'i', 0xFF}},
+		{"dw $1234, 'A'", []byte{0x34, 0x12, 0x41, 0x00}},
+		{"ds 3, $AA", []byte{0xAA, 0xAA, 0xAA}},
+		{"db HIGH($1234), LOW($1234), %101 << 1, 7 % 4, (1 + 2) * 3", []byte{0x12, 0x34, 0x0A, 0x03, 0x09}},
+	}
+	for _, test := range tests {
+		program, err := Assemble(test.source)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.source, err)
+			continue
+		}
+		if got := program.Sections[0].Data; !bytes.Equal(got, test.expected) {
+			t.Errorf("%s: want % X, got % X", test.source, test.expected, got)
+		}
+	}
+}
+
+func TestAssembleLabelsAndSections(t *testing.T) {
+	program, err := Assemble(`
+COUNT EQU 3
+DEF TARGET EQU wBuffer + 1
+
+SECTION "Entry", ROM0[$0100]
+Start:
+	ld b, COUNT
+.loop:                 ; local to Start
+	dec b
+	jr nz, .loop
+	jp Main
+
+SECTION "Main", ROM0[$0150]
+Main::
+	ld hl, wBuffer
+	ld [TARGET], a
+.loop
+	jr .loop
+	jp Start.loop
+
+SECTION "Buffer", WRAM0
+wBuffer: ds 4
+`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	labels := map[string]types.Word{
+		"Start": 0x100,
+		"Start.loop": 0x102,
+		"Main": 0x150,
+		"Main.loop": 0x156,
+		"wBuffer": 0xC000,
+	}
+	for name, address := range labels {
+		if program.Labels[name] != address {
+			t.Errorf("Label %s: want %s, got %s", name, address, program.Labels[name])
+		}
+	}
+
+	image := program.Image(0x100, 0x60)
+	expected := map[int][]byte{
+		0x00: {0x06, 0x03},             // ld b, 3
+		0x02: {0x05},                   // dec b
+		0x03: {0x20, 0xFD},             // jr nz, .loop
+		0x05: {0xC3, 0x50, 0x01},       // jp Main
+		0x50: {0x21, 0x00, 0xC0},       // ld hl, wBuffer
+		0x53: {0xEA, 0x01, 0xC0},       // ld [TARGET], a
+		0x56: {0x18, 0xFE},             // jr .loop
+		0x58: {0xC3, 0x02, 0x01},       // jp Start.loop
+	}
+	for offset, want := range expected {
+		if got := image[offset:offset+len(want)]; !bytes.Equal(got, want) {
+			t.Errorf("$%04X: want % X, got % X", 0x100 + offset, want, got)
+		}
+	}
+	if len(program.Sections) != 3 || len(program.Sections[2].Data) != 4 {
+		t.Errorf("Expected 3 sections with a 4 byte buffer, got %+v", program.Sections)
+	}
+}
+
+func TestAssembleErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		source string
+		line int
+	}{
+		{"unknown instruction", "nop\nld (bc), b", 2},
+		{"undefined label", "jp Nowhere", 1},
+		{"byte out of range", "ld a, 256", 1},
+		{"jump too far", "jr Far\nds 200\nFar:", 1},
+		{"duplicate label", "Here:\nHere:", 2},
+		{"local without global", ".loop: nop", 1},
+		{"not the high page", "ldh a, [$C000]", 1},
+		{"recursive constant", "A1 EQU B1\nB1 EQU A1\ndb A1", 3},
+		{"overlapping sections", "SECTION \"a\", ROM0[0]\nds 4\nSECTION \"b\", ROM0[2]\nnop", 0},
+	}
+	for _, test := range tests {
+		_, err := Assemble(test.source)
+		if err == nil {
+			t.Errorf("%s: expected an error", test.name)
+			continue
+		}
+		if asmErr, ok := err.(*Error); ok && asmErr.Line != test.line {
+			t.Errorf("%s: want error on line %d, got %v", test.name, test.line, err)
+		}
+	}
+}
+
+// Everything the disassembler prints should assemble back to the same bytes
+func TestAssembleDisassembly(t *testing.T) {
+	for code := 0; code < 256; code++ {
+		operand := byte(0x12)
+		if code == 0x10 {
+			// STOP's padding byte is always written as 0
+			operand = 0x00
+		}
+		for _, program := range [][]byte{{byte(code), operand, 0x34}, {0xCB, byte(code)}} {
+			inst, err := disasm.Disassemble(disasm.Bytes(program), 0)
+			if err != nil {
+				t.Fatalf("Error disassembling % X: %v", program, err)
+			}
+			assembled, err := Assemble(inst.Text)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", inst.Text, err)
+				continue
+			}
+			if got := assembled.Sections[0].Data; !bytes.Equal(got, inst.Bytes) {
+				t.Errorf("%s: want % X, got % X", inst.Text, inst.Bytes, got)
+			}
+		}
+	}
+}
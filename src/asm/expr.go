@@ -0,0 +1,281 @@
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Resolves symbols while evaluating an expression
+type scope interface {
+	symbol(name string) (int, error)
+	currentAddress() int
+}
+
+type tokenKind int
+const (
+	numberToken tokenKind = iota
+	identToken
+	opToken
+	endToken
+)
+
+var operators = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true, "&": true, "|": true,
+	"^": true, "~": true, "(": true, ")": true, "<<": true, ">>": true,
+}
+
+type token struct {
+	kind tokenKind
+	text string
+	value int
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '#'
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '$' && i+1 < len(runes) && isHexDigit(runes[i+1]):
+			start := i + 1
+			for i++; i < len(runes) && isHexDigit(runes[i]); i++ {
+			}
+			value, err := strconv.ParseInt(string(runes[start:i]), 16, 64)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: numberToken, value: int(value)})
+		case r == '%' && i+1 < len(runes) && (runes[i+1] == '0' || runes[i+1] == '1') &&
+			(len(tokens) == 0 || tokens[len(tokens)-1].kind == opToken && tokens[len(tokens)-1].text != ")"):
+			// Binary literal rather than modulo, which only follows an operand
+			start := i + 1
+			for i++; i < len(runes) && (runes[i] == '0' || runes[i] == '1'); i++ {
+			}
+			value, err := strconv.ParseInt(string(runes[start:i]), 2, 64)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: numberToken, value: int(value)})
+		case unicode.IsDigit(r):
+			start := i
+			for ; i < len(runes) && (isHexDigit(runes[i]) || runes[i] == 'x' || runes[i] == 'X' ||
+				runes[i] == 'b' || runes[i] == 'B'); i++ {
+			}
+			// Leading zeroes are decimal, not octal
+			text := strings.TrimLeft(string(runes[start:i]), "0")
+			base := 10
+			if len(text) > 0 && (text[0] == 'x' || text[0] == 'X' || text[0] == 'b' || text[0] == 'B') {
+				text = "0" + text
+				base = 0
+			}
+			if text == "" {
+				text = "0"
+			}
+			value, err := strconv.ParseInt(text, base, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Bad number %q", string(runes[start:i]))
+			}
+			tokens = append(tokens, token{kind: numberToken, value: int(value)})
+		case r == '\'':
+			if i+2 >= len(runes) || runes[i+2] != '\'' {
+				return nil, fmt.Errorf("Bad character literal in %q", expr)
+			}
+			tokens = append(tokens, token{kind: numberToken, value: int(runes[i+1])})
+			i += 3
+		case r == '@':
+			tokens = append(tokens, token{kind: identToken, text: "@"})
+			i++
+		case isIdentStart(r):
+			start := i
+			for ; i < len(runes) && isIdentPart(runes[i]); i++ {
+			}
+			tokens = append(tokens, token{kind: identToken, text: string(runes[start:i])})
+		default:
+			op := string(r)
+			if i+1 < len(runes) && (op+string(runes[i+1]) == "<<" || op+string(runes[i+1]) == ">>") {
+				op += string(runes[i+1])
+			}
+			if !operators[op] {
+				return nil, fmt.Errorf("Unexpected %q in %q", op, expr)
+			}
+			tokens = append(tokens, token{kind: opToken, text: op})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: endToken}), nil
+}
+
+func isHexDigit(r rune) bool {
+	return unicode.IsDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+// Binary operators from lowest to highest precedence
+var precedence = [][]string{
+	{"|"},
+	{"^"},
+	{"&"},
+	{"<<", ">>"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+type parser struct {
+	tokens []token
+	pos int
+	scope scope
+}
+
+func evaluate(expr string, s scope) (int, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return 0, err
+	}
+	p := &parser{tokens: tokens, scope: s}
+	value, err := p.binary(0)
+	if err != nil {
+		return 0, err
+	}
+	if p.peek().kind != endToken {
+		return 0, fmt.Errorf("Unexpected %q in %q", p.peek().text, expr)
+	}
+	return value, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != endToken {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(op string) error {
+	if t := p.next(); t.kind != opToken || t.text != op {
+		return fmt.Errorf("Expected %q", op)
+	}
+	return nil
+}
+
+func (p *parser) binary(level int) (int, error) {
+	if level == len(precedence) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		t := p.peek()
+		matched := false
+		for _, op := range precedence[level] {
+			if t.kind == opToken && t.text == op {
+				matched = true
+			}
+		}
+		if !matched {
+			return left, nil
+		}
+		p.next()
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return 0, err
+		}
+		switch t.text {
+		case "|":
+			left |= right
+		case "^":
+			left ^= right
+		case "&":
+			left &= right
+		case "<<":
+			left <<= uint(right)
+		case ">>":
+			left >>= uint(right)
+		case "+":
+			left += right
+		case "-":
+			left -= right
+		case "*":
+			left *= right
+		case "/", "%":
+			if right == 0 {
+				return 0, fmt.Errorf("Division by zero")
+			}
+			if t.text == "/" {
+				left /= right
+			} else {
+				left %= right
+			}
+		}
+	}
+}
+
+func (p *parser) unary() (int, error) {
+	t := p.peek()
+	if t.kind == opToken && (t.text == "-" || t.text == "+" || t.text == "~") {
+		p.next()
+		value, err := p.unary()
+		switch t.text {
+		case "-":
+			value = -value
+		case "~":
+			value = ^value
+		}
+		return value, err
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (int, error) {
+	t := p.next()
+	switch t.kind {
+	case numberToken:
+		return t.value, nil
+	case identToken:
+		if t.text == "@" {
+			return p.scope.currentAddress(), nil
+		}
+		// HIGH() and LOW() pick a byte out of a word
+		if name := strings.ToUpper(t.text); (name == "HIGH" || name == "LOW") &&
+			p.peek().kind == opToken && p.peek().text == "(" {
+			p.next()
+			value, err := p.binary(0)
+			if err != nil {
+				return 0, err
+			}
+			if err := p.expect(")"); err != nil {
+				return 0, err
+			}
+			if name == "HIGH" {
+				return (value >> 8) & 0xFF, nil
+			}
+			return value & 0xFF, nil
+		}
+		return p.scope.symbol(t.text)
+	case opToken:
+		if t.text == "(" {
+			value, err := p.binary(0)
+			if err != nil {
+				return 0, err
+			}
+			return value, p.expect(")")
+		}
+	}
+	return 0, fmt.Errorf("Unexpected end of expression")
+}
@@ -0,0 +1,106 @@
+package cpu
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type OpCodeStatus string
+const (
+	Implemented OpCodeStatus = "implemented"
+	Missing OpCodeStatus = "missing"
+	Illegal OpCodeStatus = "illegal"
+)
+
+// Coverage of a single opcode. Name and length come from the opcode itself
+// when it's implemented and from the instruction spec otherwise. Cycles always
+// come from the spec, as opcodes only report them when run
+type OpCodeCoverage struct {
+	Table string `json:"table"`
+	Code string `json:"code"`
+	Status OpCodeStatus `json:"status"`
+	Name string `json:"name"`
+	Length int `json:"length"`
+	Cycles int `json:"cycles"`
+	CyclesNotTaken int `json:"cycles_not_taken"`
+}
+
+type CoverageReport struct {
+	OpCodes []OpCodeCoverage `json:"opcodes"`
+	Implemented int `json:"implemented"`
+	Missing int `json:"missing"`
+	Illegal int `json:"illegal"`
+	// Percentage of the legal opcodes that are implemented
+	Percent float64 `json:"percent"`
+}
+
+// Reports which of the main and CB opcodes the CPU implements
+func (c *Cpu) Coverage() CoverageReport {
+	report := CoverageReport{}
+	addTable := func(table string, instructions [256]Instruction, codes map[byte]OpCode) {
+		for code := 0; code < 256; code++ {
+			inst := instructions[code]
+			entry := OpCodeCoverage{
+				Table: table,
+				Code: fmt.Sprintf("0x%02X", code),
+				Name: inst.Mnemonic,
+				Length: inst.Length,
+				Cycles: inst.Cycles,
+				CyclesNotTaken: inst.CyclesNotTaken,
+			}
+			op, exists := codes[byte(code)]
+			switch {
+			case inst.Illegal():
+				entry.Status = Illegal
+				report.Illegal++
+			case !exists:
+				entry.Status = Missing
+				report.Missing++
+			default:
+				entry.Status = Implemented
+				entry.Name = op.Name()
+				entry.Length = op.Length()
+				report.Implemented++
+			}
+			report.OpCodes = append(report.OpCodes, entry)
+		}
+	}
+	addTable("main", MainInstructions, c.codes)
+	addTable("cb", CBInstructions, c.cbCodes)
+
+	if legal := report.Implemented + report.Missing; legal > 0 {
+		report.Percent = 100 * float64(report.Implemented) / float64(legal)
+	}
+	return report
+}
+
+func (r CoverageReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// One row per opcode, followed by a summary row with table "summary". Only the
+// summary row fills in the percent column
+func (r CoverageReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"table", "code", "status", "name", "length", "cycles", "cycles_not_taken", "percent"})
+	for _, op := range r.OpCodes {
+		writer.Write([]string{
+			op.Table,
+			op.Code,
+			string(op.Status),
+			op.Name,
+			strconv.Itoa(op.Length),
+			strconv.Itoa(op.Cycles),
+			strconv.Itoa(op.CyclesNotTaken),
+			"",
+		})
+	}
+	writer.Write([]string{"summary", "", "", "", "", "", "", strconv.FormatFloat(r.Percent, 'f', 2, 64)})
+	writer.Flush()
+	return writer.Error()
+}
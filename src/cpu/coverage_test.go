@@ -0,0 +1,51 @@
+package cpu
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	cpu, _ := setupCpu()
+	report := cpu.Coverage()
+	if len(report.OpCodes) != 512 {
+		t.Fatalf("Expected 512 opcodes, got %d", len(report.OpCodes))
+	}
+	if report.Illegal != 11 || report.Missing != 0 || report.Percent != 100 {
+		t.Errorf("Expected 11 illegal, 0 missing and 100%%, got %d, %d and %.2f%%",
+			report.Illegal, report.Missing, report.Percent)
+	}
+
+	delete(cpu.codes, 0x00)
+	delete(cpu.cbCodes, 0x37)
+	report = cpu.Coverage()
+	if report.Missing != 2 || report.Implemented != 499 {
+		t.Errorf("Expected 2 missing and 499 implemented, got %d and %d", report.Missing, report.Implemented)
+	}
+	if op := report.OpCodes[0x100+0x37]; op.Status != Missing || op.Name != "SWAP A" || op.Table != "cb" {
+		t.Errorf("Expected missing cb SWAP A, got %+v", op)
+	}
+	if op := report.OpCodes[0xD3]; op.Status != Illegal {
+		t.Errorf("Expected 0xD3 to be illegal, got %+v", op)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("Error writing CSV: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Error reading CSV back: %v", err)
+	}
+	// Header, opcodes and summary
+	if len(rows) != 514 {
+		t.Fatalf("Expected 514 CSV rows, got %d", len(rows))
+	}
+	if row := rows[1]; row[1] != "0x00" || row[2] != "missing" || row[3] != "NOP" || row[7] != "" {
+		t.Errorf("Unexpected first row %v", row)
+	}
+	if row := rows[513]; row[0] != "summary" || row[3] != "" || row[7] != "99.60" {
+		t.Errorf("Unexpected summary row %v", row)
+	}
+}
@@ -1,13 +1,12 @@
 package cpu
 
 import (
-	"memory"
-	"log"
 	"types"
 	"fmt"
 	"utils"
 	"text/tabwriter"
 	"os"
+	"io"
 )
 
 const (
@@ -35,8 +34,9 @@ func FlagEnumToName(flag int) string {
 
 type OpCode interface {
 	// Execute the operation and return the number of cycles consumed,
-	// or an error if one occurs.
-	Run(cpu *Cpu) (cycles int, err error)
+	// or an error if one occurs. pcModified is true if the operation set
+	// PC itself, otherwise the caller advances PC past the instruction.
+	Run(cpu *Cpu) (cycles int, pcModified bool, err error)
 	Name() string
 	Length() int
 }
@@ -44,16 +44,22 @@ type OpCode interface {
 type Register8Bit struct {
 	Name string
 	value byte
+	// Bits that don't exist in hardware and always read as zero, i.e. the
+	// low nibble of F
+	zeroBits byte
 }
 
+// A pair of 8 bit registers sharing their storage, with the first named as
+// the high byte (A in AF, B in BC...). SP and PC get halves of their own that
+// no instruction addresses directly
 type Register16Bit struct {
 	Name string
-	lsb *byte
-	msb *byte
+	high *Register8Bit
+	low *Register8Bit
 }
 
 func (r *Register8Bit) Assign(value byte) {
-	r.value = value
+	r.value = value &^ r.zeroBits
 }
 
 func (r *Register8Bit) Retrieve() byte {
@@ -62,7 +68,7 @@ func (r *Register8Bit) Retrieve() byte {
 
 func (r *Register8Bit) Increment() (zero bool, halfCarry bool) {
 	result := utils.Add8Bit(r.value, 0x1)
-	r.value = result.Result
+	r.Assign(result.Result)
 	return result.Zero, result.HalfCarry
 }
 
@@ -72,92 +78,86 @@ func (r *Register8Bit) Decrement() (zero bool, halfCarry bool) {
 	zeroFlag := calc == 0
 	halfCarryFlag := (calc^0x01^r.value)&0x10 == 0x10
 
-	r.value = calc
+	r.Assign(calc)
 	return zeroFlag, halfCarryFlag
 }
 
-func (r *Register8Bit) SetBit(bit byte, value bool) {
+func (r *Register8Bit) SetBit(bit byte, value bool) error {
 	if uint(bit) > 7 {
-		log.Fatalf("Set bit maximum is 7! Got: %d", uint(bit))
+		return &InvalidBitError{Register: r.Name, Bit: bit}
 	}
 	if value {
-		r.value |= (1 << uint(bit))
+		r.Assign(r.value | (1 << uint(bit)))
 	} else {
-		r.value &= ^(1 << uint(bit))
+		r.Assign(r.value &^ (1 << uint(bit)))
 	}
+	return nil
 }
 
-func (r *Register8Bit) GetBit(bit byte) bool {
+func (r *Register8Bit) GetBit(bit byte) (bool, error) {
 	if uint(bit) > 7 {
-		log.Fatalf("Get bit maximum is 7! Got: %d", uint(bit))
+		return false, &InvalidBitError{Register: r.Name, Bit: bit}
 	}
-	return r.value & (1 << uint(bit)) != 0x00
+	return r.value & (1 << uint(bit)) != 0x00, nil
 }
 
-
-func (r *Register8Bit) IncrementValue(*memory.Memory) (bool, bool, int) {
+func (r *Register8Bit) IncrementValue(*Cpu) (bool, bool, int) {
 	zero, halfCarry := r.Increment()
 	return zero, halfCarry, 0
 }
 
-func (r *Register8Bit) DecrementValue(*memory.Memory) (bool, bool, int) {
+func (r *Register8Bit) DecrementValue(*Cpu) (bool, bool, int) {
 	zero, halfCarry := r.Decrement()
 	return zero, halfCarry, 0
 }
 
 func (r *Register16Bit) Assign(val types.Word) {
 	lsb, msb := val.ToBytes()
-	*r.lsb = lsb
-	*r.msb =  msb
+	r.high.Assign(msb)
+	r.low.Assign(lsb)
 }
 
 func (r *Register16Bit) Retrieve() (types.Word) {
-	return types.WordFromBytes(*r.lsb, *r.msb)
+	return types.WordFromBytes(r.low.Retrieve(), r.high.Retrieve())
 }
 
 func (r *Register16Bit) Increment() {
-	newVal := types.WordFromBytes(*r.lsb, *r.msb) + types.Word(1)
-	lsb, msb := newVal.ToBytes()
-	*r.lsb = lsb
-	*r.msb = msb
+	r.Assign(r.Retrieve() + 1)
 }
 
 func (r *Register16Bit) Decrement() {
-	newVal := types.WordFromBytes(*r.lsb, *r.msb) - types.Word(1)
-	lsb, msb := newVal.ToBytes()
-	*r.lsb = lsb
-	*r.msb = msb
+	r.Assign(r.Retrieve() - 1)
 }
 
-func (r *Register16Bit) IncrementValue(mem *memory.Memory) (zero bool, halfCarry bool, cycles int) {
-	val, _ := mem.Get(r.Retrieve())
+func (r *Register16Bit) IncrementValue(cpu *Cpu) (zero bool, halfCarry bool, cycles int) {
+	val, _ := cpu.readByte(r.Retrieve())
 	result := utils.Add8Bit(val, byte(1))
-	mem.Set(r.Retrieve(), result.Result)
+	cpu.writeByte(r.Retrieve(), result.Result)
 	return result.Zero, result.HalfCarry, 8
 }
 
-func (r *Register16Bit) DecrementValue(mem *memory.Memory) (zero bool, halfCarry bool, cycles int) {
-	val, _ := mem.Get(r.Retrieve())
+func (r *Register16Bit) DecrementValue(cpu *Cpu) (zero bool, halfCarry bool, cycles int) {
+	val, _ := cpu.readByte(r.Retrieve())
 	result := utils.Subtract8Bit(val, byte(1))
-	mem.Set(r.Retrieve(), result.Result)
+	cpu.writeByte(r.Retrieve(), result.Result)
 	return result.Zero, result.HalfCarry, 8
 }
 
 // Wrapper that lets us use either an 8 bit register directly or a 16bit one as an address
 type ByteSource interface {
-	GetValue(mem *memory.Memory) (value byte, cycles int)
+	GetValue(cpu *Cpu) (value byte, cycles int)
 	// Annoyingly need to use something besides Name() because both registers already have that field
 	PrintableName() string
-	SetValue(mem *memory.Memory, value byte) (cycles int)
-	IncrementValue(mem *memory.Memory) (zero bool, halfCarry bool, cycles int)
-	DecrementValue(mem *memory.Memory) (zero bool, halfCarry bool, cycles int)
+	SetValue(cpu *Cpu, value byte) (cycles int)
+	IncrementValue(cpu *Cpu) (zero bool, halfCarry bool, cycles int)
+	DecrementValue(cpu *Cpu) (zero bool, halfCarry bool, cycles int)
 }
 
-func (r *Register8Bit) GetValue(*memory.Memory) (byte, int) {
+func (r *Register8Bit) GetValue(*Cpu) (byte, int) {
 	return r.value, 0 // No extra cycle cost
 }
 
-func (r *Register8Bit) SetValue(_ *memory.Memory, val byte) (int) {
+func (r *Register8Bit) SetValue(_ *Cpu, val byte) (int) {
 	r.Assign(val)
 	return 0
 }
@@ -166,14 +166,13 @@ func (r *Register8Bit) PrintableName() string {
 	return r.Name
 }
 
-func (r *Register16Bit) GetValue(mem *memory.Memory) (byte, int) {
-	// TODO handle errors?
-	val, _ := mem.Get(r.Retrieve())
+func (r *Register16Bit) GetValue(cpu *Cpu) (byte, int) {
+	val, _ := cpu.readByte(r.Retrieve())
 	return val, 4
 }
 
-func (r *Register16Bit) SetValue(mem *memory.Memory, value byte) (int) {
-	mem.Set(r.Retrieve(), value)
+func (r *Register16Bit) SetValue(cpu *Cpu, value byte) (int) {
+	cpu.writeByte(r.Retrieve(), value)
 	return 4
 }
 
@@ -181,13 +180,227 @@ func (r *Register16Bit) PrintableName() string {
 	return fmt.Sprintf("(%s)", r.Name)
 }
 
+// The byte following the opcode, i.e. d8
+type ImmediateByte struct {
+	pc *Register16Bit
+}
+
+func (r *ImmediateByte) GetValue(cpu *Cpu) (byte, int) {
+	val, _ := cpu.fetchByte(r.pc.Retrieve() + 1)
+	return val, 4
+}
+
+// Immediates are read-only, so the remaining ByteSource methods do nothing
+func (r *ImmediateByte) SetValue(*Cpu, byte) int {
+	return 0
+}
+
+func (r *ImmediateByte) IncrementValue(*Cpu) (bool, bool, int) {
+	return false, false, 0
+}
+
+func (r *ImmediateByte) DecrementValue(*Cpu) (bool, bool, int) {
+	return false, false, 0
+}
+
+func (r *ImmediateByte) PrintableName() string {
+	return "d8"
+}
+
+// Memory at the 16 bit address following the opcode, i.e. (a16)
+type ImmediateAddress struct {
+	pc *Register16Bit
+}
+
+func (r *ImmediateAddress) address(cpu *Cpu) (types.Word, int) {
+	lsb, _ := cpu.fetchByte(r.pc.Retrieve() + 1)
+	msb, _ := cpu.fetchByte(r.pc.Retrieve() + 2)
+	return types.WordFromBytes(lsb, msb), 8
+}
+
+func (r *ImmediateAddress) GetValue(cpu *Cpu) (byte, int) {
+	address, cycles := r.address(cpu)
+	val, _ := cpu.readByte(address)
+	return val, cycles + 4
+}
+
+func (r *ImmediateAddress) SetValue(cpu *Cpu, value byte) int {
+	address, cycles := r.address(cpu)
+	cpu.writeByte(address, value)
+	return cycles + 4
+}
+
+func (r *ImmediateAddress) IncrementValue(cpu *Cpu) (bool, bool, int) {
+	val, getCycles := r.GetValue(cpu)
+	result := utils.Add8Bit(val, byte(1))
+	setCycles := r.SetValue(cpu, result.Result)
+	return result.Zero, result.HalfCarry, getCycles + setCycles
+}
+
+func (r *ImmediateAddress) DecrementValue(cpu *Cpu) (bool, bool, int) {
+	val, getCycles := r.GetValue(cpu)
+	result := utils.Subtract8Bit(val, byte(1))
+	setCycles := r.SetValue(cpu, result.Result)
+	return result.Zero, result.HalfCarry, getCycles + setCycles
+}
+
+func (r *ImmediateAddress) PrintableName() string {
+	return "(a16)"
+}
+
+// Memory in the 0xFF00 page where the I/O registers live. The offset comes
+// from a register for (C) or from the byte following the opcode for (a8)
+type HighPageAddress struct {
+	pc *Register16Bit
+	offset *Register8Bit // nil for the immediate byte
+}
+
+func (r *HighPageAddress) address(cpu *Cpu) (types.Word, int) {
+	if r.offset != nil {
+		return types.WordFromBytes(r.offset.Retrieve(), 0xFF), 0
+	}
+	offset, _ := cpu.fetchByte(r.pc.Retrieve() + 1)
+	return types.WordFromBytes(offset, 0xFF), 4
+}
+
+func (r *HighPageAddress) GetValue(cpu *Cpu) (byte, int) {
+	address, cycles := r.address(cpu)
+	val, _ := cpu.readByte(address)
+	return val, cycles + 4
+}
+
+func (r *HighPageAddress) SetValue(cpu *Cpu, value byte) int {
+	address, cycles := r.address(cpu)
+	cpu.writeByte(address, value)
+	return cycles + 4
+}
+
+func (r *HighPageAddress) IncrementValue(cpu *Cpu) (bool, bool, int) {
+	val, getCycles := r.GetValue(cpu)
+	result := utils.Add8Bit(val, byte(1))
+	setCycles := r.SetValue(cpu, result.Result)
+	return result.Zero, result.HalfCarry, getCycles + setCycles
+}
+
+func (r *HighPageAddress) DecrementValue(cpu *Cpu) (bool, bool, int) {
+	val, getCycles := r.GetValue(cpu)
+	result := utils.Subtract8Bit(val, byte(1))
+	setCycles := r.SetValue(cpu, result.Result)
+	return result.Zero, result.HalfCarry, getCycles + setCycles
+}
+
+func (r *HighPageAddress) PrintableName() string {
+	if r.offset != nil {
+		return fmt.Sprintf("(%s)", r.offset.Name)
+	}
+	return "(a8)"
+}
+
+// Everything the CPU reads and writes goes through this, e.g. memory.Bus or
+// a flat memory.Memory in tests
+type Bus interface {
+	Get(address types.Word) (byte, error)
+	Set(address types.Word, value byte) error
+}
+
 type Cpu struct {
 	// more fields to come
-	memory *memory.Memory
+	memory Bus
 	A, B, C, D, E, F, H, L Register8Bit
 	AF, BC, DE, HL, SP, PC Register16Bit
 	codes map[byte]OpCode
-}
+	// Opcodes following the 0xCB prefix
+	cbCodes map[byte]OpCode
+	// Total number of cycles executed since the CPU was created
+	cycles uint64
+	ticker Ticker
+	cycleAccurate bool
+	// Cycles already passed to the ticker during the current step
+	stepTicked int
+	// Interrupt master enable, plus EI's request to set it after the next instruction
+	ime, imePending bool
+	// IF and IE
+	interruptFlags, interruptEnable byte
+	// Set by HALT and STOP until an interrupt wakes the CPU
+	halted, stopped bool
+	// HALT with IME clear and an interrupt already pending fails to
+	// increment PC after the next opcode fetch
+	haltBug bool
+	// Set after running an illegal opcode under LockUpOnIllegal
+	locked bool
+	illegalPolicy IllegalOpCodePolicy
+	breakHandler BreakHandler
+	// Gameboy Doctor style log of every instruction, nil when not tracing
+	traceWriter io.Writer
+	// First error hit by something that can't return one, e.g. a bus fault
+	// in a ByteSource. Step returns it once the instruction finishes
+	faultErr error
+	// Ring buffer of the last instructions run, for crash dumps
+	history []executedInstruction
+	historyNext int
+	crashDump *CrashDump
+	// Memory access observers, nil when there aren't any so the check is cheap
+	observers []registeredObserver
+	nextObserverId ObserverId
+	// PC at the start of the current instruction, for observers
+	instructionPC types.Word
+}
+
+// Returned by Step when the byte at PC doesn't map to a known opcode
+type UnknownOpCodeError struct {
+	PC types.Word
+	Code byte
+}
+
+func (e *UnknownOpCodeError) Error() string {
+	return fmt.Sprintf("Unknown opcode 0x%02X at %s", e.Code, e.PC)
+}
+
+// Returned by Step for one of the opcodes that don't exist on the hardware,
+// when the policy is ErrorOnIllegal
+type IllegalOpCodeError struct {
+	PC types.Word
+	Code byte
+}
+
+func (e *IllegalOpCodeError) Error() string {
+	return fmt.Sprintf("Illegal opcode 0x%02X at %s", e.Code, e.PC)
+}
+
+// Recorded by SetFlag or GetFlag for anything besides Z, N, H and C
+type InvalidFlagError struct {
+	Flag int
+}
+
+func (e *InvalidFlagError) Error() string {
+	return fmt.Sprintf("Invalid flag %d", e.Flag)
+}
+
+// Returned by SetBit and GetBit for a bit past 7
+type InvalidBitError struct {
+	Register string
+	Bit byte
+}
+
+func (e *InvalidBitError) Error() string {
+	return fmt.Sprintf("Invalid bit %d of register %s", e.Bit, e.Register)
+}
+
+// What to do when an illegal opcode is executed
+type IllegalOpCodePolicy int
+const (
+	// Lock up like the hardware does: the clock keeps running but nothing
+	// is ever fetched again
+	LockUpOnIllegal IllegalOpCodePolicy = iota
+	// Return an IllegalOpCodeError from Step
+	ErrorOnIllegal
+	// Call the break handler with PC left on the illegal opcode. Falls back
+	// to ErrorOnIllegal if no handler is set
+	BreakOnIllegal
+)
+
+// Called to hand control to a debugger
+type BreakHandler func(cpu *Cpu, pc types.Word, code byte)
 
 type BaseOpCode struct {
 	code byte
@@ -200,7 +413,7 @@ func (b BaseOpCode) Length() int {
 
 
 // Initializes a default CPU
-func NewCpu(memory *memory.Memory) (*Cpu) {
+func NewCpu(memory Bus) (*Cpu) {
 	cpu := &Cpu{
 		memory: memory,
 	}
@@ -214,10 +427,12 @@ func NewCpu(memory *memory.Memory) (*Cpu) {
 	cpu.H.Name = "H"
 	cpu.L.Name = "L"
 
-	setupMixedRegister := func(mixed *Register16Bit, lsb *Register8Bit, msb *Register8Bit) {
-		mixed.lsb = &lsb.value
-		mixed.msb = &msb.value
-		mixed.Name = lsb.Name + msb.Name
+	cpu.F.zeroBits = 0x0F
+
+	setupMixedRegister := func(mixed *Register16Bit, high *Register8Bit, low *Register8Bit) {
+		mixed.high = high
+		mixed.low = low
+		mixed.Name = high.Name + low.Name
 	}
 
 	setupMixedRegister(&cpu.AF, &cpu.A, &cpu.F)
@@ -225,65 +440,68 @@ func NewCpu(memory *memory.Memory) (*Cpu) {
 	setupMixedRegister(&cpu.DE, &cpu.D, &cpu.E)
 	setupMixedRegister(&cpu.HL, &cpu.H, &cpu.L)
 
-	var spLsb, spMsb, pcLsb, pcMsb byte
-
-	cpu.SP.lsb = &spLsb
-	cpu.SP.msb = &spMsb
-	cpu.PC.lsb = &pcLsb
-	cpu.PC.msb = &pcMsb
+	cpu.SP = Register16Bit{Name: "SP", high: &Register8Bit{}, low: &Register8Bit{}}
+	cpu.PC = Register16Bit{Name: "PC", high: &Register8Bit{}, low: &Register8Bit{}}
 
 	cpu.codes = cpu.generateOpCodes()
+	cpu.cbCodes = cpu.generateCBOpCodes()
+	cpu.SetHistorySize(defaultHistorySize)
 
 	return cpu
 }
 
 func (c *Cpu) LoadImmediateByte() (byte, error) {
 	pc := types.Word(c.PC.Retrieve())
-	return c.memory.Get(pc + types.Word(1))
+	return c.fetchByte(pc + types.Word(1))
 }
 
 func (c *Cpu) LoadImmediateWord() (types.Word, error) {
 	pc := types.Word(c.PC.Retrieve())
-	lsb, err := c.memory.Get(pc + types.Word(1))
+	lsb, err := c.fetchByte(pc + types.Word(1))
 	if err != nil {
 		return types.Word(0), err
 	}
-	msb, err := c.memory.Get(pc + types.Word(2))
+	msb, err := c.fetchByte(pc + types.Word(2))
 	if err != nil {
 		return types.Word(0), err
 	}
 	return types.WordFromBytes(lsb, msb), nil
 }
 
-func (c *Cpu) SetFlag(flag int, value bool) {
+// Maps a flag to its bit in F
+func flagBit(flag int) (byte, bool) {
 	switch flag {
 	case Z:
-		c.F.SetBit(7, value)
+		return 7, true
 	case N:
-		c.F.SetBit(6, value)
+		return 6, true
 	case H:
-		c.F.SetBit(5, value)
+		return 5, true
 	case C:
-		c.F.SetBit(4, value)
-	default:
-		log.Fatalf("Unknown flag: %c", flag)
+		return 4, true
+	}
+	return 0, false
+}
+
+// An unknown flag is a bug in an opcode, so rather than making every caller
+// check, it faults the current Step which returns an InvalidFlagError
+func (c *Cpu) SetFlag(flag int, value bool) {
+	bit, ok := flagBit(flag)
+	if !ok {
+		c.fault(&InvalidFlagError{Flag: flag})
+		return
 	}
+	c.F.SetBit(bit, value)
 }
 
 func (c *Cpu) GetFlag(flag int) bool {
-	switch flag {
-	case Z:
-		return c.F.GetBit(7)
-	case N:
-		return c.F.GetBit(6)
-	case H:
-		return c.F.GetBit(5)
-	case C:
-		return c.F.GetBit(4)
-	default:
-		log.Fatalf("Unknown flag: %c", flag)
-		return false // think I need this for the compiler
+	bit, ok := flagBit(flag)
+	if !ok {
+		c.fault(&InvalidFlagError{Flag: flag})
+		return false
 	}
+	value, _ := c.F.GetBit(bit)
+	return value
 }
 
 func (c *Cpu) IncrementPC(instructionSize int) {
@@ -293,443 +511,172 @@ func (c *Cpu) IncrementPC(instructionSize int) {
 	// TODO: check if we're wrapping around?
 }
 
-func (c *Cpu) generateOpCodes() map[byte]OpCode {
-	codes := make(map[byte]OpCode)
-	// LD n,n
-	{
-		// Order of source registers
-		sourceRegisters := []*Register8Bit{
-			&c.B, &c.C, &c.D, &c.E, &c.H, &c.L, nil, &c.A}
-		destRegisters := []*Register8Bit{
-			&c.B, &c.C, &c.D, &c.E, &c.H, &c.L, nil, &c.A}
-
-		codeMsb := 0x04
-		codeLsb := 0x00
-		for index, destRegister := range destRegisters {
-			for _, srcRegister := range sourceRegisters {
-				code := byte(codeLsb + (codeMsb * 16))
-				codeLsb++
-				if srcRegister == nil || destRegister == nil {
-					continue
-				}
-				op := &Ld8BitRegisterOpCode{
-					BaseOpCode: BaseOpCode{
-						code: code,
-						length: 1,
-					},
-					r1: destRegister,
-					r2: srcRegister,
-				}
-				codes[code] = op
-			}
-			if index % 2 == 1 {
-				codeMsb++
-				codeLsb = 0
-			}
-		}
+// Push a word onto the stack, high byte first
+func (c *Cpu) PushWord(value types.Word) error {
+	lsb, msb := value.ToBytes()
+	c.SP.Decrement()
+	if err := c.writeByte(c.SP.Retrieve(), msb); err != nil {
+		return err
 	}
+	c.SP.Decrement()
+	return c.writeByte(c.SP.Retrieve(), lsb)
+}
 
-	// 8 bit immediate loads
-	{
-		// Map of code LSB to dest registers
-		lsbToDestRegisters := map[int][]*Register8Bit{
-			0x6: {&c.B, &c.D, &c.H},
-			0xE: {&c.C, &c.E, &c.L, &c.A},
-		}
-		for codeLsb, destRegisters := range lsbToDestRegisters {
-			codeMsb := 0x0
-			for _, destRegister := range destRegisters {
-				code := byte(codeLsb + (codeMsb * 16))
-				codeMsb++
-				op := &Ld8BitImmediateOpCode{
-					BaseOpCode: BaseOpCode{
-						code: code,
-						length: 2,
-					},
-					r1: destRegister,
-				}
-				codes[code] = op
-			}
-		}
+// Pop a word off the stack, low byte first
+func (c *Cpu) PopWord() (types.Word, error) {
+	lsb, err := c.readByte(c.SP.Retrieve())
+	if err != nil {
+		return types.Word(0), err
 	}
-
-	// Memory immediate load
-	{
-		code := byte(0x36)
-		codes[code] = &LdMemoryImmediateOpCode{
-			BaseOpCode: BaseOpCode {
-				code: code,
-				length: 2,
-			},
-			r1: &c.HL,
-		}
+	c.SP.Increment()
+	msb, err := c.readByte(c.SP.Retrieve())
+	if err != nil {
+		return types.Word(0), err
 	}
+	c.SP.Increment()
+	return types.WordFromBytes(lsb, msb), nil
+}
 
-	// Reg into memory loads for BC and DE
-	{
-		srcRegisters := []*Register16Bit{&c.BC, &c.DE}
-		codeLsb := 0x2
-		codeMsb := 0x0
-		for _, srcRegister := range srcRegisters {
-			code := byte(codeLsb + (codeMsb * 16))
-			codeMsb++
-			codes[code] = &LdRegIntoMemOpCode{
-				BaseOpCode: BaseOpCode{
-					code: code,
-					length: 1,
-				},
-				r1: srcRegister,
-				r2: &c.A,
-			}
-		}
+func (c *Cpu) ConditionMet(condition Condition) bool {
+	switch condition {
+	case NotZero:
+		return !c.GetFlag(Z)
+	case Zero:
+		return c.GetFlag(Z)
+	case NoCarry:
+		return !c.GetFlag(C)
+	case Carry:
+		return c.GetFlag(C)
+	default:
+		return true
 	}
+}
 
-	// Reg into memory loads for HL
-	{
-		srcRegisters := []*Register8Bit{&c.B, &c.C, &c.D, &c.E, &c.H, &c.L, nil, &c.A}
-		codeLsb := 0x0
-		codeMsb := 0x7
-		for _, srcRegister := range srcRegisters {
-			code := byte(codeLsb + (codeMsb * 16))
-			codeLsb++
-			if srcRegister == nil {
-				continue
-			}
-			codes[code] = &LdRegIntoMemOpCode{
-				BaseOpCode: BaseOpCode{
-					code: code,
-					length: 1,
-				},
-				r1: &c.HL,
-				r2: srcRegister,
-			}
-		}
+// Fetch the opcode at PC, execute it and advance PC past it (unless the
+// opcode jumped). Returns the number of cycles consumed. If an interrupt is
+// serviced instead the step consists of just the dispatch to its vector.
+func (c *Cpu) Step() (int, error) {
+	c.stepTicked = 0
+	c.faultErr = nil
+	if c.locked {
+		return c.finishStep(4), nil
 	}
-
-	// Reg into memory loads for HL+
-	{
-		code := byte(0x22)
-		codes[code] = &LdRegIntoMemOpCode{
-			BaseOpCode: BaseOpCode{
-				code: code,
-				length: 1,
-			},
-			incrementR1: true,
-			r1: &c.HL,
-			r2: &c.A,
+	if c.stopped {
+		// Only a joypad press gets us out of STOP
+		if c.interruptFlags & (1 << Joypad) == 0 {
+			return c.finishStep(4), nil
 		}
+		c.stopped = false
 	}
-
-	// Reg into memory loads for HL-
-	{
-		code := byte(0x32)
-		codes[code] = &LdRegIntoMemOpCode{
-			BaseOpCode: BaseOpCode{
-				code: code,
-				length: 1,
-			},
-			decrementR1: true,
-			r1: &c.HL,
-			r2: &c.A,
+	if c.halted {
+		// Keep the clock running until an interrupt is pending, whether or
+		// not IME is set
+		if c.pendingInterrupts() == 0 {
+			return c.finishStep(4), nil
 		}
+		c.halted = false
 	}
 
-	// Memory into reg for BC and DE
-	{
-		codeMsb := 0x0
-		codeLsb := 0xA
-		srcRegisters := []*Register16Bit{&c.BC, &c.DE}
-		for _, srcRegister := range srcRegisters {
-			code := byte(codeLsb + 16 * codeMsb)
-			codeMsb++
-			codes[code] = &LdMemIntoRegOpCode{
-				BaseOpCode: BaseOpCode{
-					code: code,
-					length: 1,
-				},
-				r1: &c.A,
-				r2: srcRegister,
-			}
-		}
+	c.instructionPC = c.PC.Retrieve()
+	dispatched, err := c.dispatchInterrupt()
+	if err == nil {
+		err = c.faultErr
 	}
-
-	// Memory into reg for regular HL
-	{
-		lsbToRegisters := map[int][]*Register8Bit{
-			0x6: {&c.B, &c.D, &c.H},
-			0xE: {&c.C, &c.E, &c.L, &c.A},
-		}
-		for codeLsb, destRegisters := range lsbToRegisters {
-			codeMsb := 0x4
-			for _, destRegister := range destRegisters {
-				code := byte(codeLsb + 16 * codeMsb)
-				codeMsb++
-				codes[code] = &LdMemIntoRegOpCode{
-					BaseOpCode: BaseOpCode{
-						code: code,
-						length: 1,
-					},
-					r1: destRegister,
-					r2: &c.HL,
-				}
-			}
-		}
+	if err != nil {
+		return 0, c.crash(err)
 	}
-
-	// LD A,(HL+)
-	{
-		code := byte(0x2A)
-		codes[code] = &LdMemIntoRegOpCode{
-			BaseOpCode: BaseOpCode{
-				code: code,
-				length: 1,
-			},
-			r1: &c.A,
-			r2: &c.HL,
-			incrementR2: true,
-		}
+	if dispatched {
+		return c.finishStep(interruptDispatchCycles), nil
 	}
 
-	// LD A,(HL-)
-	{
-		code := byte(0x3A)
-		codes[code] = &LdMemIntoRegOpCode{
-			BaseOpCode: BaseOpCode{
-				code: code,
-				length: 1,
-			},
-			r1: &c.A,
-			r2: &c.HL,
-			decrementR2: true,
-		}
-	}
+	// EI only takes effect once the instruction after it has run
+	enableInterrupts := c.imePending
 
-	// ADD A,n
-	{
-		otherRegs := []ByteSource{&c.B, &c.C, &c.D, &c.E, &c.H, &c.L, &c.HL, &c.A}
-		codeMsb := 0x8
-		codeLsb := 0x0
-		for _, otherReg := range otherRegs {
-			code := byte(codeLsb + 16*codeMsb)
-			codeLsb++
-			codes[code] = &Add8BitRegOpCode{
-				BaseOpCode: BaseOpCode{
-					code: code,
-					length: 1,
-				},
-				r1: &c.A,
-				r2: otherReg,
-			}
-		}
+	if err := c.trace(); err != nil {
+		return 0, err
 	}
-
-	// SUB n, SBC n
-	{
-		otherRegs := []ByteSource{&c.B, &c.C, &c.D, &c.E, &c.H, &c.L, &c.HL, &c.A}
-		codeMsb := 0x9
-		codeLsb := 0x0
-		for offset, otherReg := range otherRegs {
-			codeNoCarry := byte(codeLsb + codeMsb * 16 + offset)
-			codeWithCarry := byte(codeLsb + codeMsb * 16 + len(otherRegs) + offset)
-			codes[codeNoCarry] = &Sub8BitRegOpCode{
-				BaseOpCode: BaseOpCode{
-					code: codeNoCarry,
-				},
-				r1: &c.A,
-				r2: otherReg,
-				includeCarry: false,
-			}
-			codes[codeWithCarry] = &Sub8BitRegOpCode{
-				BaseOpCode: BaseOpCode{
-					code: codeWithCarry,
-				},
-				r1: &c.A,
-				r2: otherReg,
-				includeCarry: true,
-			}
-		}
+	pc := c.PC.Retrieve()
+	code, err := c.fetchByte(pc)
+	if err != nil {
+		return 0, c.crash(err)
+	}
+	c.recordHistory(pc)
+	op, exists := c.codes[code]
+	if !exists {
+		return 0, c.crash(&UnknownOpCodeError{
+			PC: pc,
+			Code: code,
+		})
+	}
+	if c.haltBug {
+		// Rewind PC so the opcode byte is read again as the first byte of
+		// its operand, and the instruction ends one byte short
+		c.haltBug = false
+		c.PC.Assign(pc - 1)
+	}
+	cycles, pcModified, err := op.Run(c)
+	if err == nil {
+		err = c.faultErr
 	}
-
-
-	// ADC A,n
-	{
-		otherRegs := []ByteSource{&c.B, &c.C, &c.D, &c.E, &c.H, &c.L, &c.HL, &c.A}
-		codeMsb := 0x8
-		codeLsb := 0x8
-		for _, otherReg := range otherRegs {
-			code := byte(codeLsb + 16*codeMsb)
-			codeLsb++
-			if otherReg == nil {
-				continue
-			}
-			codes[code] = &Add8BitRegOpCode{
-				BaseOpCode: BaseOpCode{
-					code: code,
-					length: 1,
-				},
-				r1: &c.A,
-				r2: otherReg,
-				includeCarry: true,
-			}
-		}
+	if err != nil {
+		return 0, c.crash(err)
 	}
-
-	// Logical ops, AND, XOR, OR, and CP
-	{
-		buildOp := func(code byte, sourceReg ByteSource, op LogicalOp) *Logical8BitOp {
-			return &Logical8BitOp{
-				BaseOpCode: BaseOpCode{
-					code: code,
-					length: 1,
-				},
-				target: &c.A,
-				source: sourceReg,
-				operation: op,
-			}
-		}
-		sourceRegs := []ByteSource{&c.B, &c.C, &c.D, &c.E, &c.H, &c.L, &c.HL, &c.A}
-		codeMsb := 0xA
-		codeLsb := 0x0
-		for _, sourceReg := range sourceRegs {
-			code := byte(codeLsb + 16*codeMsb)
-			codeLsb++
-			codes[code] = buildOp(code, sourceReg, AND)
-		}
-
-		for _, sourceReg := range sourceRegs {
-			code := byte(codeLsb + 16 * codeMsb)
-			codeLsb++
-			codes[code] = buildOp(code, sourceReg, XOR)
-		}
-
-		for _, sourceReg := range sourceRegs {
-			code := byte(codeLsb + 16 * codeMsb)
-			codeLsb++
-			codes[code] = buildOp(code, sourceReg, OR)
-		}
-
-		for _, sourceReg := range sourceRegs {
-			code := byte(codeLsb + 16 * codeMsb)
-			codeLsb++
-			codes[code] = buildOp(code, sourceReg, CP)
-		}
+	if !pcModified {
+		c.IncrementPC(op.Length())
 	}
-
-	// LD n,d16
-	{
-		sourceRegs := []*Register16Bit{&c.BC, &c.DE, &c.HL, &c.SP}
-		codeLsb := 0x1
-		for codeMsb, sourceReg := range sourceRegs {
-			code := byte(codeLsb + 16 * codeMsb)
-			codes[code] = &Ld16BitImmediateOpCode{
-				BaseOpCode: BaseOpCode{
-					code: code,
-				},
-				r1: sourceReg,
-			}
-		}
+	if enableInterrupts && c.imePending {
+		c.ime = true
+		c.imePending = false
 	}
+	return c.finishStep(cycles), nil
+}
 
-	// INC and DEC 16 bit
-	{
-		regs := []*Register16Bit{&c.BC, &c.DE, &c.HL, &c.SP}
-		incLsb := 0x3
-		decLsb := 0xB
-		for codeMsb, reg := range regs {
-			incCode := byte(incLsb + 16 * codeMsb)
-			decCode := byte(decLsb + 16 * codeMsb)
-			codes[incCode] = &IncDec16Bit{
-				BaseOpCode: BaseOpCode{
-					code: incCode,
-				},
-				target: reg,
-				mod: Increment,
-			}
-			codes[decCode] = &IncDec16Bit{
-				BaseOpCode: BaseOpCode{
-					code: decCode,
-				},
-				target: reg,
-				mod: Decrement,
-			}
-		}
-	}
+func (c *Cpu) SetIllegalOpCodePolicy(policy IllegalOpCodePolicy) {
+	c.illegalPolicy = policy
+}
 
-	{
-		lsbToRegs := map[int][]ByteSource{
-			0x4: []ByteSource{&c.B, &c.D, &c.H, &c.HL},
-			0xC: []ByteSource{&c.C, &c.E, &c.L, &c.A},
-		}
-		// DEC regs LSB is +1 from INC
-		for codeLsb, regs := range lsbToRegs {
-			for codeMsb, reg := range regs {
-				incCode := byte(codeLsb + 16 * codeMsb)
-				decCode := byte(codeLsb + 1 + 16 * codeMsb)
-				codes[incCode] = &Inc8BitRegOpCode{
-					BaseOpCode: BaseOpCode{
-						code: incCode,
-					},
-					r1: reg,
-				}
-				codes[decCode] = &Dec8BitRegOpCode{
-					BaseOpCode: BaseOpCode{
-						code: decCode,
-					},
-					r1: reg,
-				}
-			}
-		}
-	}
+// Handler used by BreakOnIllegal
+func (c *Cpu) SetBreakHandler(handler BreakHandler) {
+	c.breakHandler = handler
+}
 
-	// NOP
-	{
-		code := byte(0x00)
-		codes[code] = &NoOpCode{
-			BaseOpCode: BaseOpCode{
-				code: code,
-			},
-		}
-	}
+// Whether the CPU has locked up after an illegal opcode
+func (c *Cpu) Locked() bool {
+	return c.locked
+}
 
-	// RL{C}A and RR{C}A
-	{
-		makeOp := func(code byte, dir Direction, carry bool) {
-			codes[code] = &RotateOpCode{
-				BaseOpCode: BaseOpCode{
-					code: code,
-				},
-				r1: &c.A,
-				direction: dir,
-				includeCarry: carry,
-				isCB: false,
-			}
-		}
-		makeOp(0x07, Left, true)
-		makeOp(0x17, Left, false)
-		makeOp(0x0F, Right, true)
-		makeOp(0x1F, Right, false)
-	}
-
-	// ADD nn,nn for 16 bit
-	{
-		sourceRegisters := []*Register16Bit{&c.BC, &c.DE, &c.HL, &c.SP}
-		codeLsb := 0x9
-		for codeMsb, reg := range sourceRegisters {
-			code := byte(codeMsb * 16 + codeLsb)
-			codes[code] = &Add16BitRegOpCode{
-				BaseOpCode: BaseOpCode{
-					code: code,
-				},
-				r1: &c.HL,
-				r2: reg,
-			}
+func (c *Cpu) handleIllegalOpCode(code byte) (int, bool, error) {
+	pc := c.PC.Retrieve()
+	switch c.illegalPolicy {
+	case LockUpOnIllegal:
+		c.locked = true
+		return 4, true, nil
+	case BreakOnIllegal:
+		if c.breakHandler != nil {
+			c.breakHandler(c, pc, code)
+			return 4, true, nil
 		}
 	}
-	
+	return -1, false, &IllegalOpCodeError{
+		PC: pc,
+		Code: code,
+	}
+}
 
-	return codes
+// Whether the CPU is waiting in HALT for an interrupt
+func (c *Cpu) Halted() bool {
+	return c.halted
 }
 
+// Whether the CPU is in STOP's low power mode waiting for a button press
+func (c *Cpu) Stopped() bool {
+	return c.stopped
+}
+
+// Total number of cycles executed so far
+func (c *Cpu) Cycles() uint64 {
+	return c.cycles
+}
 
 // Utility function
 func OpCodesByName(codes map[byte]OpCode) map[string]OpCode {
@@ -741,11 +688,17 @@ func OpCodesByName(codes map[byte]OpCode) map[string]OpCode {
 }
 
 func (c *Cpu) PrintKnownOpCodes() {
+	printOpCodeTable(c.codes)
+	fmt.Fprintf(os.Stdout, "\nCB prefixed:\n")
+	printOpCodeTable(c.cbCodes)
+}
+
+func printOpCodeTable(codes map[byte]OpCode) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug)
 	for msb := 0; msb < 16; msb++ {
 		for lsb := 0; lsb < 16; lsb++ {
 			index := byte(16*msb + lsb)
-			if code, exists := c.codes[index]; exists {
+			if code, exists := codes[index]; exists {
 				fmt.Fprintf(w, "0x%02x - %s", index, code.Name())
 			} else {
 				fmt.Fprintf(w, "0x%02x - Missing", index)
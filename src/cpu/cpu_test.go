@@ -4,7 +4,6 @@ import (
 	"testing"
 	"memory"
 	"fmt"
-	"log"
 	"math/rand"
 	"types"
 )
@@ -21,16 +20,7 @@ func setupCpu() (*Cpu, *memory.Memory) {
 func setupCpuWithState(state SystemState) (*Cpu, *memory.Memory) {
 	memory := memory.SetupBlankMemory(mockMemorySize)
 	cpu := NewCpu(memory)
-	cpu.A.Assign(state.A)
-	cpu.B.Assign(state.B)
-	cpu.C.Assign(state.C)
-	cpu.D.Assign(state.D)
-	cpu.E.Assign(state.E)
-	cpu.F.Assign(state.F)
-	cpu.H.Assign(state.H)
-	cpu.L.Assign(state.L)
-	cpu.SP.Assign(state.SP)
-	cpu.PC.Assign(state.PC)
+	cpu.SetRegisters(state.registers())
 	for address, val := range state.memVals {
 		memory.Set(address, val)
 	}
@@ -77,7 +67,7 @@ func checkCpuFlags(t *testing.T, cpu *Cpu, flagChanges FlagChanges, originalFlag
 		if expectedFlagState == FlagUnchanged {
 			if getOriginalFlag(flag) != flagVal {
 				t.Errorf("Flag %s incorrectly modified, want: %t, got: %t",
-					flag, getOriginalFlag(flag), flagVal)
+					FlagEnumToName(flag), getOriginalFlag(flag), flagVal)
 			}
 		} else {
 			expectedFlagValue := expectedFlagState == FlagTrue
@@ -94,38 +84,16 @@ func checkCpuFlags(t *testing.T, cpu *Cpu, flagChanges FlagChanges, originalFlag
 }
 
 func checkCpuState(t *testing.T, cpu *Cpu, mem *memory.Memory, expectedState SystemState) {
-	if cpu.A.Retrieve() != expectedState.A {
-		t.Errorf("Register A incorrect, want: 0x%x, got: 0x%x", expectedState.A, cpu.A.Retrieve())
+	got := cpu.Registers()
+	want := expectedState.registers()
+	// Only check F if it's explicitly set, and leave IME and HALT to the
+	// tests that care about them
+	if want.F == 0 {
+		want.F = got.F
 	}
-	if cpu.B.Retrieve() != expectedState.B {
-		t.Errorf("Register B incorrect, want: 0x%x, got: 0x%x", expectedState.B, cpu.B.Retrieve())
-	}
-	if cpu.C.Retrieve() != expectedState.C {
-		t.Errorf("Register C incorrect, want: 0x%x, got: 0x%x", expectedState.C, cpu.C.Retrieve())
-	}
-	if cpu.D.Retrieve() != expectedState.D {
-		t.Errorf("Register D incorrect, want: 0x%x, got: 0x%x", expectedState.D, cpu.D.Retrieve())
-	}
-	if cpu.E.Retrieve() != expectedState.E {
-		t.Errorf("Register E incorrect, want: 0x%x, got: 0x%x", expectedState.E, cpu.E.Retrieve())
-	}
-	if cpu.H.Retrieve() != expectedState.H {
-		t.Errorf("Register H incorrect, want: 0x%x, got: 0x%x", expectedState.H, cpu.H.Retrieve())
-	}
-	if cpu.L.Retrieve() != expectedState.L {
-		t.Errorf("Register L incorrect, want: 0x%x, got: 0x%x", expectedState.L, cpu.L.Retrieve())
-	}
-
-	// Only check F if it's explicitly set
-	if expectedState.F != 0 && cpu.F.Retrieve() != expectedState.F {
-		t.Errorf("Register F incorrect, want: 0x%x, got: 0x%x", expectedState.F, cpu.F.Retrieve())
-	}
-	
-	if cpu.SP.Retrieve() != expectedState.SP {
-		t.Errorf("Register SP incorrect, want: 0x%x, got: 0x%x", expectedState.SP, cpu.SP.Retrieve())
-	}
-	if cpu.PC.Retrieve() != expectedState.PC {
-		t.Errorf("Register PC incorrect, want: 0x%x, got: 0x%x", expectedState.PC, cpu.PC.Retrieve())
+	want.IME, want.Halted = got.IME, got.Halted
+	if !want.Equal(got) {
+		t.Errorf("Registers incorrect, %s", want.Diff(got))
 	}
 	for address, expectedVal := range expectedState.memVals {
 		if memVal, err := mem.Get(address); err != nil {
@@ -588,6 +556,13 @@ type SystemState struct {
 	memVals map[types.Word]byte
 }
 
+func (s SystemState) registers() Registers {
+	return Registers{
+		A: s.A, F: s.F, B: s.B, C: s.C, D: s.D, E: s.E, H: s.H, L: s.L,
+		SP: s.SP, PC: s.PC,
+	}
+}
+
 type FlagChanges struct {
 	ZeroFlag, SubtractFlag, HalfCarryFlag, CarryFlag FlagState
 }
@@ -699,3 +674,1545 @@ func TestAdd8BitRegOpCodes(t *testing.T) {
 		})
 	}
 };
+
+func TestStep(t *testing.T) {
+	cpu, _ := setupCpuWithState(SystemState{
+		PC: types.Word(0x10),
+		memVals: map[types.Word]byte{
+			0x10: 0x06, // LD B,d8
+			0x11: 0x3C,
+			0x12: 0x04, // INC B
+			0x13: 0x00, // NOP
+		},
+	})
+
+	expectedSteps := []struct{
+		cycles int
+		pc types.Word
+	} {
+		{cycles: 8, pc: 0x12},
+		{cycles: 4, pc: 0x13},
+		{cycles: 4, pc: 0x14},
+	}
+	for _, expected := range expectedSteps {
+		cycles, err := cpu.Step()
+		if err != nil {
+			t.Fatalf("Error stepping cpu: %v", err)
+		}
+		if cycles != expected.cycles {
+			t.Errorf("Incorrect number of cycles, want: %d, got: %d", expected.cycles, cycles)
+		}
+		if cpu.PC.Retrieve() != expected.pc {
+			t.Errorf("Incorrect PC, want: %s, got: %s", expected.pc, cpu.PC.Retrieve())
+		}
+	}
+
+	if cpu.B.Retrieve() != 0x3D {
+		t.Errorf("Register B incorrect, want: 0x3d, got: 0x%x", cpu.B.Retrieve())
+	}
+	if cpu.Cycles() != 16 {
+		t.Errorf("Incorrect total cycles, want: 16, got: %d", cpu.Cycles())
+	}
+}
+
+func TestStep_unknownOpCode(t *testing.T) {
+	cpu, _ := setupCpuWithState(SystemState{
+		PC: types.Word(0x20),
+		memVals: map[types.Word]byte{
+			0x20: 0xD3,
+		},
+	})
+	// Every opcode is in the table, so pretend this one isn't
+	delete(cpu.codes, 0xD3)
+
+	cycles, err := cpu.Step()
+	if err == nil {
+		t.Fatalf("Expected error stepping unknown opcode, got %d cycles", cycles)
+	}
+	unknownErr, ok := err.(*UnknownOpCodeError)
+	if !ok {
+		t.Fatalf("Error was of incorrect type, want: *UnknownOpCodeError, got: %T", err)
+	}
+	if unknownErr.PC != types.Word(0x20) || unknownErr.Code != 0xD3 {
+		t.Errorf("Incorrect error contents, want: 0xD3 at 0x0020, got: %v", unknownErr)
+	}
+	if cpu.PC.Retrieve() != types.Word(0x20) {
+		t.Errorf("PC modified, want: 0x0020, got: %s", cpu.PC.Retrieve())
+	}
+	if cpu.Cycles() != 0 {
+		t.Errorf("Cycles counted for unknown opcode, want: 0, got: %d", cpu.Cycles())
+	}
+}
+
+func TestCBOpCodes(t *testing.T) {
+	testCases := []struct{
+		name string
+		code string
+		cycles int
+		startState, endState SystemState
+		flagChanges FlagChanges
+	} {
+		{
+			name: "Rotate left circular",
+			code: "RLC B",
+			cycles: 8,
+			startState: SystemState{
+				B: byte(0x85),
+			},
+			endState: SystemState{
+				B: byte(0x0B),
+			},
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagFalse,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagFalse,
+				CarryFlag: FlagTrue,
+			},
+		}, {
+			name: "Rotate right through carry",
+			code: "RR C",
+			cycles: 8,
+			startState: SystemState{
+				C: byte(0x01),
+			},
+			endState: SystemState{
+				C: byte(0x00),
+			},
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagTrue,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagFalse,
+				CarryFlag: FlagTrue,
+			},
+		}, {
+			name: "Arithmetic shift right keeps sign",
+			code: "SRA D",
+			cycles: 8,
+			startState: SystemState{
+				D: byte(0x8A),
+			},
+			endState: SystemState{
+				D: byte(0xC5),
+			},
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagFalse,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagFalse,
+				CarryFlag: FlagFalse,
+			},
+		}, {
+			name: "Logical shift right",
+			code: "SRL A",
+			cycles: 8,
+			startState: SystemState{
+				A: byte(0x8B),
+			},
+			endState: SystemState{
+				A: byte(0x45),
+			},
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagFalse,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagFalse,
+				CarryFlag: FlagTrue,
+			},
+		}, {
+			name: "Shift left in memory",
+			code: "SLA (HL)",
+			cycles: 16,
+			startState: SystemState{
+				H: byte(0x01),
+				L: byte(0x23),
+				memVals: map[types.Word]byte{
+					0x123: 0x80,
+				},
+			},
+			endState: SystemState{
+				H: byte(0x01),
+				L: byte(0x23),
+				memVals: map[types.Word]byte{
+					0x123: 0x00,
+				},
+			},
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagTrue,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagFalse,
+				CarryFlag: FlagTrue,
+			},
+		}, {
+			name: "Swap nibbles",
+			code: "SWAP E",
+			cycles: 8,
+			startState: SystemState{
+				E: byte(0xF1),
+			},
+			endState: SystemState{
+				E: byte(0x1F),
+			},
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagFalse,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagFalse,
+				CarryFlag: FlagFalse,
+			},
+		}, {
+			name: "Test clear bit",
+			code: "BIT 7,H",
+			cycles: 8,
+			startState: SystemState{
+				H: byte(0x7F),
+			},
+			endState: SystemState{
+				H: byte(0x7F),
+			},
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagTrue,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagTrue,
+				CarryFlag: FlagUnchanged,
+			},
+		}, {
+			name: "Test set bit in memory",
+			code: "BIT 2,(HL)",
+			cycles: 12,
+			startState: SystemState{
+				H: byte(0x02),
+				L: byte(0x10),
+				memVals: map[types.Word]byte{
+					0x210: 0x04,
+				},
+			},
+			endState: SystemState{
+				H: byte(0x02),
+				L: byte(0x10),
+				memVals: map[types.Word]byte{
+					0x210: 0x04,
+				},
+			},
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagFalse,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagTrue,
+				CarryFlag: FlagUnchanged,
+			},
+		}, {
+			name: "Reset bit in memory",
+			code: "RES 0,(HL)",
+			cycles: 16,
+			startState: SystemState{
+				H: byte(0x02),
+				L: byte(0x10),
+				memVals: map[types.Word]byte{
+					0x210: 0xFF,
+				},
+			},
+			endState: SystemState{
+				H: byte(0x02),
+				L: byte(0x10),
+				memVals: map[types.Word]byte{
+					0x210: 0xFE,
+				},
+			},
+		}, {
+			name: "Set bit",
+			code: "SET 3,L",
+			cycles: 8,
+			startState: SystemState{
+				L: byte(0x00),
+			},
+			endState: SystemState{
+				L: byte(0x08),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cpu, mem := setupCpuWithState(tc.startState)
+			originalFlagState := setupCpuFlags(cpu, tc.flagChanges)
+			codesByName := OpCodesByName(cpu.cbCodes)
+			code, exists := codesByName[tc.code]
+			if !exists {
+				t.Fatalf("Could not find code '%s'", tc.code)
+			}
+			if code.Length() != 2 {
+				t.Errorf("Incorrect length, want: 2, got: %d", code.Length())
+			}
+			cycles, pcModified, err := code.Run(cpu)
+			if err != nil {
+				t.Fatalf("Error running opcode: %v", err)
+			}
+			if cycles != tc.cycles {
+				t.Errorf("Incorrect number of cycles, want: %d, got: %d", tc.cycles, cycles)
+			}
+			if pcModified {
+				t.Error("PC incorrectly modified, want: false, got: true")
+			}
+			checkCpuFlags(t, cpu, tc.flagChanges, originalFlagState)
+			checkCpuState(t, cpu, mem, tc.endState)
+		})
+	}
+}
+
+func TestGenerateCBOpCodes(t *testing.T) {
+	cpu, _ := setupCpu()
+	if len(cpu.cbCodes) != 256 {
+		t.Errorf("Incomplete CB table, want: 256 codes, got: %d", len(cpu.cbCodes))
+	}
+	expectedNames := map[byte]string{
+		0x00: "RLC B",
+		0x0E: "RRC (HL)",
+		0x17: "RL A",
+		0x1B: "RR E",
+		0x26: "SLA (HL)",
+		0x2F: "SRA A",
+		0x37: "SWAP A",
+		0x3C: "SRL H",
+		0x46: "BIT 0,(HL)",
+		0x7F: "BIT 7,A",
+		0x9A: "RES 3,D",
+		0xC1: "SET 0,C",
+		0xFE: "SET 7,(HL)",
+	}
+	for index, expectedName := range expectedNames {
+		if code, exists := cpu.cbCodes[index]; !exists {
+			t.Errorf("Could not find CB opcode 0x%02x", index)
+		} else if code.Name() != expectedName {
+			t.Errorf("Code name incorrect for 0x%02x, want: %s, got: %s", index, expectedName, code.Name())
+		}
+	}
+}
+
+func TestStep_cbPrefix(t *testing.T) {
+	cpu, _ := setupCpuWithState(SystemState{
+		A: byte(0x01),
+		PC: types.Word(0x30),
+		memVals: map[types.Word]byte{
+			0x30: 0xCB,
+			0x31: 0xFF, // SET 7,A
+		},
+	})
+
+	cycles, err := cpu.Step()
+	if err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cycles != 8 {
+		t.Errorf("Incorrect number of cycles, want: 8, got: %d", cycles)
+	}
+	if cpu.A.Retrieve() != 0x81 {
+		t.Errorf("Register A incorrect, want: 0x81, got: 0x%x", cpu.A.Retrieve())
+	}
+	if cpu.PC.Retrieve() != types.Word(0x32) {
+		t.Errorf("Incorrect PC, want: 0x0032, got: %s", cpu.PC.Retrieve())
+	}
+}
+
+func TestRotateAccumulatorOpCodes(t *testing.T) {
+	testCases := []struct{
+		code string
+		startA, endA byte
+		carryIn, carryOut bool
+	} {
+		{code: "RLCA", startA: 0x85, endA: 0x0B, carryIn: false, carryOut: true},
+		{code: "RLA", startA: 0x85, endA: 0x0A, carryIn: false, carryOut: true},
+		{code: "RLA", startA: 0x05, endA: 0x0B, carryIn: true, carryOut: false},
+		{code: "RRCA", startA: 0x01, endA: 0x80, carryIn: false, carryOut: true},
+		{code: "RRA", startA: 0x01, endA: 0x00, carryIn: false, carryOut: true},
+		{code: "RRA", startA: 0x02, endA: 0x81, carryIn: true, carryOut: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			cpu, _ := setupCpu()
+			cpu.A.Assign(tc.startA)
+			cpu.SetFlag(C, tc.carryIn)
+			cpu.SetFlag(Z, true)
+			code := OpCodesByName(cpu.codes)[tc.code]
+			cycles, _, err := code.Run(cpu)
+			if err != nil {
+				t.Fatalf("Error running opcode: %v", err)
+			}
+			if cycles != 4 {
+				t.Errorf("Incorrect number of cycles, want: 4, got: %d", cycles)
+			}
+			if cpu.A.Retrieve() != tc.endA {
+				t.Errorf("Register A incorrect, want: 0x%x, got: 0x%x", tc.endA, cpu.A.Retrieve())
+			}
+			if cpu.GetFlag(C) != tc.carryOut {
+				t.Errorf("Incorrect carry flag, want: %t, got: %t", tc.carryOut, cpu.GetFlag(C))
+			}
+			if cpu.GetFlag(Z) {
+				t.Errorf("Incorrect zero flag, want: false, got: true")
+			}
+		})
+	}
+}
+
+func TestControlFlowOpCodes(t *testing.T) {
+	testCases := []struct{
+		name string
+		cycles int
+		startState, endState SystemState
+	} {
+		{
+			name: "JP a16",
+			cycles: 16,
+			startState: SystemState{
+				PC: types.Word(0x10),
+				memVals: map[types.Word]byte{0x10: 0xC3, 0x11: 0x50, 0x12: 0x01},
+			},
+			endState: SystemState{
+				PC: types.Word(0x150),
+			},
+		}, {
+			name: "JP NZ,a16 not taken",
+			cycles: 12,
+			startState: SystemState{
+				F: byte(0x80), // Z set
+				PC: types.Word(0x10),
+				memVals: map[types.Word]byte{0x10: 0xC2, 0x11: 0x50, 0x12: 0x01},
+			},
+			endState: SystemState{
+				PC: types.Word(0x13),
+			},
+		}, {
+			name: "JP (HL)",
+			cycles: 4,
+			startState: SystemState{
+				H: byte(0x01),
+				L: byte(0x01),
+				PC: types.Word(0x10),
+				memVals: map[types.Word]byte{0x10: 0xE9},
+			},
+			endState: SystemState{
+				H: byte(0x01),
+				L: byte(0x01),
+				PC: types.Word(0x101),
+			},
+		}, {
+			name: "JR r8 backwards",
+			cycles: 12,
+			startState: SystemState{
+				PC: types.Word(0x20),
+				memVals: map[types.Word]byte{0x20: 0x18, 0x21: 0xFC},
+			},
+			endState: SystemState{
+				PC: types.Word(0x1E),
+			},
+		}, {
+			name: "JR C,r8 not taken",
+			cycles: 8,
+			startState: SystemState{
+				PC: types.Word(0x20),
+				memVals: map[types.Word]byte{0x20: 0x38, 0x21: 0x05},
+			},
+			endState: SystemState{
+				PC: types.Word(0x22),
+			},
+		}, {
+			name: "JR Z,r8 taken",
+			cycles: 12,
+			startState: SystemState{
+				F: byte(0x80),
+				PC: types.Word(0x20),
+				memVals: map[types.Word]byte{0x20: 0x28, 0x21: 0x05},
+			},
+			endState: SystemState{
+				PC: types.Word(0x27),
+			},
+		}, {
+			name: "CALL a16",
+			cycles: 24,
+			startState: SystemState{
+				SP: types.Word(0x200),
+				PC: types.Word(0x40),
+				memVals: map[types.Word]byte{0x40: 0xCD, 0x41: 0x34, 0x42: 0x01},
+			},
+			endState: SystemState{
+				SP: types.Word(0x1FE),
+				PC: types.Word(0x134),
+				memVals: map[types.Word]byte{0x1FF: 0x00, 0x1FE: 0x43},
+			},
+		}, {
+			name: "CALL Z,a16 not taken",
+			cycles: 12,
+			startState: SystemState{
+				SP: types.Word(0x200),
+				PC: types.Word(0x40),
+				memVals: map[types.Word]byte{0x40: 0xCC, 0x41: 0x34, 0x42: 0x01},
+			},
+			endState: SystemState{
+				SP: types.Word(0x200),
+				PC: types.Word(0x43),
+			},
+		}, {
+			name: "RET",
+			cycles: 16,
+			startState: SystemState{
+				SP: types.Word(0x1FE),
+				PC: types.Word(0x134),
+				memVals: map[types.Word]byte{0x134: 0xC9, 0x1FE: 0x43, 0x1FF: 0x00},
+			},
+			endState: SystemState{
+				SP: types.Word(0x200),
+				PC: types.Word(0x43),
+			},
+		}, {
+			name: "RET C taken",
+			cycles: 20,
+			startState: SystemState{
+				F: byte(0x10), // C set
+				SP: types.Word(0x1FE),
+				PC: types.Word(0x134),
+				memVals: map[types.Word]byte{0x134: 0xD8, 0x1FE: 0x43, 0x1FF: 0x00},
+			},
+			endState: SystemState{
+				SP: types.Word(0x200),
+				PC: types.Word(0x43),
+			},
+		}, {
+			name: "RET NC not taken",
+			cycles: 8,
+			startState: SystemState{
+				F: byte(0x10),
+				SP: types.Word(0x1FE),
+				PC: types.Word(0x134),
+				memVals: map[types.Word]byte{0x134: 0xD0},
+			},
+			endState: SystemState{
+				SP: types.Word(0x1FE),
+				PC: types.Word(0x135),
+			},
+		}, {
+			name: "RST 38H",
+			cycles: 16,
+			startState: SystemState{
+				SP: types.Word(0x200),
+				PC: types.Word(0x60),
+				memVals: map[types.Word]byte{0x60: 0xFF},
+			},
+			endState: SystemState{
+				SP: types.Word(0x1FE),
+				PC: types.Word(0x38),
+				memVals: map[types.Word]byte{0x1FF: 0x00, 0x1FE: 0x61},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cpu, mem := setupCpuWithState(tc.startState)
+			cycles, err := cpu.Step()
+			if err != nil {
+				t.Fatalf("Error stepping cpu: %v", err)
+			}
+			if cycles != tc.cycles {
+				t.Errorf("Incorrect number of cycles, want: %d, got: %d", tc.cycles, cycles)
+			}
+			checkCpuState(t, cpu, mem, tc.endState)
+		})
+	}
+}
+
+func TestStackOpCodes(t *testing.T) {
+	testCases := []struct{
+		name string
+		program []byte
+		cycles int
+		setup func(cpu *Cpu, mem *memory.Memory)
+		check func(t *testing.T, cpu *Cpu, mem *memory.Memory)
+	} {
+		{
+			name: "PUSH BC",
+			program: []byte{0xC5},
+			cycles: 16,
+			setup: func(cpu *Cpu, mem *memory.Memory) {
+				cpu.BC.Assign(types.Word(0x1234))
+			},
+			check: func(t *testing.T, cpu *Cpu, mem *memory.Memory) {
+				checkCpuState(t, cpu, mem, SystemState{
+					B: cpu.B.Retrieve(),
+					C: cpu.C.Retrieve(),
+					SP: types.Word(0x1FE),
+					PC: types.Word(0x101),
+					memVals: map[types.Word]byte{0x1FF: 0x12, 0x1FE: 0x34},
+				})
+			},
+		}, {
+			name: "POP DE",
+			program: []byte{0xD1},
+			cycles: 12,
+			setup: func(cpu *Cpu, mem *memory.Memory) {
+				cpu.SP.Assign(types.Word(0x1FE))
+				mem.Set(types.Word(0x1FE), 0xEF)
+				mem.Set(types.Word(0x1FF), 0xBE)
+			},
+			check: func(t *testing.T, cpu *Cpu, mem *memory.Memory) {
+				if cpu.DE.Retrieve() != types.Word(0xBEEF) {
+					t.Errorf("Register DE incorrect, want: 0xBEEF, got: %s", cpu.DE.Retrieve())
+				}
+				if cpu.SP.Retrieve() != types.Word(0x200) {
+					t.Errorf("Register SP incorrect, want: 0x0200, got: %s", cpu.SP.Retrieve())
+				}
+			},
+		}, {
+			name: "POP AF clears low nibble of F",
+			program: []byte{0xF1},
+			cycles: 12,
+			setup: func(cpu *Cpu, mem *memory.Memory) {
+				cpu.SP.Assign(types.Word(0x1FE))
+				mem.Set(types.Word(0x1FE), 0xFF)
+				mem.Set(types.Word(0x1FF), 0xFF)
+			},
+			check: func(t *testing.T, cpu *Cpu, mem *memory.Memory) {
+				if cpu.A.Retrieve() != 0xFF {
+					t.Errorf("Register A incorrect, want: 0xff, got: 0x%x", cpu.A.Retrieve())
+				}
+				if cpu.F.Retrieve() != 0xF0 {
+					t.Errorf("Register F incorrect, want: 0xf0, got: 0x%x", cpu.F.Retrieve())
+				}
+			},
+		}, {
+			name: "LD (a16),SP",
+			program: []byte{0x08, 0x80, 0x00},
+			cycles: 20,
+			setup: func(cpu *Cpu, mem *memory.Memory) {
+				cpu.SP.Assign(types.Word(0xFFF8))
+			},
+			check: func(t *testing.T, cpu *Cpu, mem *memory.Memory) {
+				checkCpuState(t, cpu, mem, SystemState{
+					SP: types.Word(0xFFF8),
+					PC: types.Word(0x103),
+					memVals: map[types.Word]byte{0x80: 0xF8, 0x81: 0xFF},
+				})
+			},
+		}, {
+			name: "LD SP,HL",
+			program: []byte{0xF9},
+			cycles: 8,
+			setup: func(cpu *Cpu, mem *memory.Memory) {
+				cpu.HL.Assign(types.Word(0xC123))
+			},
+			check: func(t *testing.T, cpu *Cpu, mem *memory.Memory) {
+				if cpu.SP.Retrieve() != types.Word(0xC123) {
+					t.Errorf("Register SP incorrect, want: 0xC123, got: %s", cpu.SP.Retrieve())
+				}
+			},
+		}, {
+			name: "LD HL,SP+r8 negative offset",
+			program: []byte{0xF8, 0xFE},
+			cycles: 12,
+			setup: func(cpu *Cpu, mem *memory.Memory) {
+				cpu.SP.Assign(types.Word(0xFFF8))
+			},
+			check: func(t *testing.T, cpu *Cpu, mem *memory.Memory) {
+				if cpu.HL.Retrieve() != types.Word(0xFFF6) {
+					t.Errorf("Register HL incorrect, want: 0xFFF6, got: %s", cpu.HL.Retrieve())
+				}
+				// 0xF8 + 0xFE carries out of both bit 3 and bit 7
+				checkCpuFlags(t, cpu, FlagChanges{
+					ZeroFlag: FlagFalse,
+					SubtractFlag: FlagFalse,
+					HalfCarryFlag: FlagTrue,
+					CarryFlag: FlagTrue,
+				}, 0)
+			},
+		}, {
+			name: "ADD SP,r8",
+			program: []byte{0xE8, 0x08},
+			cycles: 16,
+			setup: func(cpu *Cpu, mem *memory.Memory) {
+				cpu.SP.Assign(types.Word(0x00F8))
+				cpu.SetFlag(Z, true)
+			},
+			check: func(t *testing.T, cpu *Cpu, mem *memory.Memory) {
+				if cpu.SP.Retrieve() != types.Word(0x0100) {
+					t.Errorf("Register SP incorrect, want: 0x0100, got: %s", cpu.SP.Retrieve())
+				}
+				checkCpuFlags(t, cpu, FlagChanges{
+					ZeroFlag: FlagFalse,
+					SubtractFlag: FlagFalse,
+					HalfCarryFlag: FlagTrue,
+					CarryFlag: FlagTrue,
+				}, 0)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cpu, mem := setupCpu()
+			cpu.SP.Assign(types.Word(0x200))
+			cpu.PC.Assign(types.Word(0x100))
+			for offset, val := range tc.program {
+				mem.Set(types.Word(0x100 + offset), val)
+			}
+			tc.setup(cpu, mem)
+			cycles, err := cpu.Step()
+			if err != nil {
+				t.Fatalf("Error stepping cpu: %v", err)
+			}
+			if cycles != tc.cycles {
+				t.Errorf("Incorrect number of cycles, want: %d, got: %d", tc.cycles, cycles)
+			}
+			tc.check(t, cpu, mem)
+		})
+	}
+}
+
+func TestAddressedLoadOpCodes(t *testing.T) {
+	testCases := []struct{
+		name string
+		program []byte
+		cycles int
+		startState, endState SystemState
+	} {
+		{
+			name: "LDH (a8),A",
+			program: []byte{0xE0, 0x47},
+			cycles: 12,
+			startState: SystemState{A: byte(0xE4)},
+			endState: SystemState{
+				A: byte(0xE4),
+				PC: types.Word(0x102),
+				memVals: map[types.Word]byte{0xFF47: 0xE4},
+			},
+		}, {
+			name: "LDH A,(a8)",
+			program: []byte{0xF0, 0x80},
+			cycles: 12,
+			startState: SystemState{
+				memVals: map[types.Word]byte{0xFF80: 0x99},
+			},
+			endState: SystemState{
+				A: byte(0x99),
+				PC: types.Word(0x102),
+			},
+		}, {
+			name: "LD (C),A",
+			program: []byte{0xE2},
+			cycles: 8,
+			startState: SystemState{A: byte(0x12), C: byte(0x10)},
+			endState: SystemState{
+				A: byte(0x12),
+				C: byte(0x10),
+				PC: types.Word(0x101),
+				memVals: map[types.Word]byte{0xFF10: 0x12},
+			},
+		}, {
+			name: "LD A,(C)",
+			program: []byte{0xF2},
+			cycles: 8,
+			startState: SystemState{
+				C: byte(0xFF),
+				memVals: map[types.Word]byte{0xFFFF: 0x1F},
+			},
+			endState: SystemState{
+				A: byte(0x1F),
+				C: byte(0xFF),
+				PC: types.Word(0x101),
+			},
+		}, {
+			name: "LD (a16),A",
+			program: []byte{0xEA, 0x00, 0xC0},
+			cycles: 16,
+			startState: SystemState{A: byte(0x42)},
+			endState: SystemState{
+				A: byte(0x42),
+				PC: types.Word(0x103),
+				memVals: map[types.Word]byte{0xC000: 0x42},
+			},
+		}, {
+			name: "LD A,(a16)",
+			program: []byte{0xFA, 0x34, 0x12},
+			cycles: 16,
+			startState: SystemState{
+				memVals: map[types.Word]byte{0x1234: 0x77},
+			},
+			endState: SystemState{
+				A: byte(0x77),
+				PC: types.Word(0x103),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.SetupBlankMemory(0x10000)
+			cpu := NewCpu(mem)
+			cpu.A.Assign(tc.startState.A)
+			cpu.C.Assign(tc.startState.C)
+			cpu.PC.Assign(types.Word(0x100))
+			for address, val := range tc.startState.memVals {
+				mem.Set(address, val)
+			}
+			for offset, val := range tc.program {
+				mem.Set(types.Word(0x100 + offset), val)
+			}
+
+			code := cpu.codes[tc.program[0]]
+			if code.Name() != tc.name {
+				t.Errorf("Code name incorrect, want: %s, got: %s", tc.name, code.Name())
+			}
+			cycles, err := cpu.Step()
+			if err != nil {
+				t.Fatalf("Error stepping cpu: %v", err)
+			}
+			if cycles != tc.cycles {
+				t.Errorf("Incorrect number of cycles, want: %d, got: %d", tc.cycles, cycles)
+			}
+			checkCpuState(t, cpu, mem, tc.endState)
+		})
+	}
+}
+
+func TestImmediateAluOpCodes(t *testing.T) {
+	testCases := []struct{
+		name string
+		program []byte
+		startA, startF, endA byte
+		flagChanges FlagChanges
+	} {
+		{
+			name: "ADD A,d8",
+			program: []byte{0xC6, 0xC6},
+			startA: 0x3A,
+			endA: 0x00,
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagTrue,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagTrue,
+				CarryFlag: FlagTrue,
+			},
+		}, {
+			name: "ADC A,d8",
+			program: []byte{0xCE, 0x0F},
+			startA: 0xE1,
+			startF: 0x10, // Carry set
+			endA: 0xF1,
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagFalse,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagTrue,
+				CarryFlag: FlagFalse,
+			},
+		}, {
+			name: "SUB d8",
+			program: []byte{0xD6, 0x3E},
+			startA: 0x3E,
+			endA: 0x00,
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagTrue,
+				SubtractFlag: FlagTrue,
+				HalfCarryFlag: FlagFalse,
+				CarryFlag: FlagFalse,
+			},
+		}, {
+			name: "SBC A,d8",
+			program: []byte{0xDE, 0x4F},
+			startA: 0x3B,
+			startF: 0x10,
+			endA: 0xEB,
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagFalse,
+				SubtractFlag: FlagTrue,
+				HalfCarryFlag: FlagTrue,
+				CarryFlag: FlagTrue,
+			},
+		}, {
+			name: "AND d8",
+			program: []byte{0xE6, 0x38},
+			startA: 0x5A,
+			endA: 0x18,
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagFalse,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagTrue,
+				CarryFlag: FlagFalse,
+			},
+		}, {
+			name: "XOR d8",
+			program: []byte{0xEE, 0xFF},
+			startA: 0xFF,
+			endA: 0x00,
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagTrue,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagFalse,
+				CarryFlag: FlagFalse,
+			},
+		}, {
+			name: "OR d8",
+			program: []byte{0xF6, 0x03},
+			startA: 0x5A,
+			endA: 0x5B,
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagFalse,
+				SubtractFlag: FlagFalse,
+				HalfCarryFlag: FlagFalse,
+				CarryFlag: FlagFalse,
+			},
+		}, {
+			name: "CP d8",
+			program: []byte{0xFE, 0x40},
+			startA: 0x3C,
+			endA: 0x3C,
+			flagChanges: FlagChanges{
+				ZeroFlag: FlagFalse,
+				SubtractFlag: FlagTrue,
+				HalfCarryFlag: FlagFalse,
+				CarryFlag: FlagTrue,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cpu, mem := setupCpu()
+			cpu.A.Assign(tc.startA)
+			originalFlagState := tc.startF
+			if originalFlagState == 0 {
+				originalFlagState = setupCpuFlags(cpu, tc.flagChanges)
+			} else {
+				cpu.F.Assign(originalFlagState)
+			}
+			cpu.PC.Assign(types.Word(0x100))
+			for offset, val := range tc.program {
+				mem.Set(types.Word(0x100 + offset), val)
+			}
+
+			code := cpu.codes[tc.program[0]]
+			if code.Name() != tc.name {
+				t.Errorf("Code name incorrect, want: %s, got: %s", tc.name, code.Name())
+			}
+			cycles, err := cpu.Step()
+			if err != nil {
+				t.Fatalf("Error stepping cpu: %v", err)
+			}
+			if cycles != 8 {
+				t.Errorf("Incorrect number of cycles, want: 8, got: %d", cycles)
+			}
+			if cpu.PC.Retrieve() != types.Word(0x102) {
+				t.Errorf("Incorrect PC, want: 0x0102, got: %s", cpu.PC.Retrieve())
+			}
+			if cpu.A.Retrieve() != tc.endA {
+				t.Errorf("Register A incorrect, want: 0x%x, got: 0x%x", tc.endA, cpu.A.Retrieve())
+			}
+			checkCpuFlags(t, cpu, tc.flagChanges, originalFlagState)
+		})
+	}
+}
+
+func TestDecimalAdjust(t *testing.T) {
+	toBCD := func(value int) byte {
+		return byte((value / 10) << 4 | (value % 10))
+	}
+	cpu, mem := setupCpu()
+	for _, subtract := range []bool{false, true} {
+		for a := 0; a < 100; a++ {
+			for b := 0; b < 100; b++ {
+				opCode := byte(0xC6) // ADD A,d8
+				expected := a + b
+				if subtract {
+					opCode = 0xD6 // SUB d8
+					expected = a - b
+				}
+				expectedCarry := expected < 0 || expected > 99
+				expected = (expected + 100) % 100
+
+				program := []byte{0x3E, toBCD(a), opCode, toBCD(b), 0x27}
+				for offset, val := range program {
+					mem.Set(types.Word(offset), val)
+				}
+				cpu.PC.Assign(types.Word(0))
+				for step := 0; step < 3; step++ {
+					if _, err := cpu.Step(); err != nil {
+						t.Fatalf("Error stepping cpu: %v", err)
+					}
+				}
+				if cpu.A.Retrieve() != toBCD(expected) || cpu.GetFlag(C) != expectedCarry {
+					t.Errorf("DAA incorrect for %02d, %02d (subtract: %t), want: 0x%02x carry: %t, got: 0x%02x carry: %t",
+						a, b, subtract, toBCD(expected), expectedCarry, cpu.A.Retrieve(), cpu.GetFlag(C))
+				}
+				if cpu.GetFlag(Z) != (expected == 0) {
+					t.Errorf("Incorrect zero flag for %02d, %02d (subtract: %t), want: %t, got: %t",
+						a, b, subtract, expected == 0, cpu.GetFlag(Z))
+				}
+				if cpu.GetFlag(H) {
+					t.Errorf("Half carry not reset for %02d, %02d (subtract: %t)", a, b, subtract)
+				}
+			}
+		}
+	}
+}
+
+func TestAccumulatorFlagOpCodes(t *testing.T) {
+	testCases := []struct{
+		code string
+		startA, startF, endA, endF byte
+	} {
+		{code: "CPL", startA: 0x35, startF: 0x90, endA: 0xCA, endF: 0xF0},
+		{code: "SCF", startA: 0x35, startF: 0xE0, endA: 0x35, endF: 0x90},
+		{code: "CCF", startA: 0x35, startF: 0x70, endA: 0x35, endF: 0x00},
+		{code: "CCF", startA: 0x35, startF: 0x80, endA: 0x35, endF: 0x90},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			cpu, _ := setupCpu()
+			cpu.A.Assign(tc.startA)
+			cpu.F.Assign(tc.startF)
+			code := OpCodesByName(cpu.codes)[tc.code]
+			cycles, _, err := code.Run(cpu)
+			if err != nil {
+				t.Fatalf("Error running opcode: %v", err)
+			}
+			if cycles != 4 {
+				t.Errorf("Incorrect number of cycles, want: 4, got: %d", cycles)
+			}
+			if cpu.A.Retrieve() != tc.endA {
+				t.Errorf("Register A incorrect, want: 0x%x, got: 0x%x", tc.endA, cpu.A.Retrieve())
+			}
+			if cpu.F.Retrieve() != tc.endF {
+				t.Errorf("Register F incorrect, want: 0x%x, got: 0x%x", tc.endF, cpu.F.Retrieve())
+			}
+		})
+	}
+}
+
+func TestInterruptDispatch(t *testing.T) {
+	cpu, mem := setupCpuWithState(SystemState{
+		SP: types.Word(0x200),
+		PC: types.Word(0x100),
+		memVals: map[types.Word]byte{
+			0x100: 0xFB, // EI
+			0x101: 0x00, // NOP
+			0x102: 0x00, // NOP
+		},
+	})
+	cpu.SetInterruptEnable(0x14) // Timer and joypad
+	cpu.RequestInterrupt(Joypad)
+	cpu.RequestInterrupt(Timer)
+	cpu.RequestInterrupt(VBlank)
+
+	// EI and the instruction after it run before anything is dispatched
+	for _, expectedPC := range []types.Word{0x101, 0x102} {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatalf("Error stepping cpu: %v", err)
+		}
+		if cpu.PC.Retrieve() != expectedPC {
+			t.Fatalf("Interrupt dispatched too early, want PC: %s, got: %s", expectedPC, cpu.PC.Retrieve())
+		}
+	}
+	if !cpu.InterruptMasterEnable() {
+		t.Fatalf("IME not set after instruction following EI")
+	}
+
+	cycles, err := cpu.Step()
+	if err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cycles != 20 {
+		t.Errorf("Incorrect number of cycles, want: 20, got: %d", cycles)
+	}
+	checkCpuState(t, cpu, mem, SystemState{
+		SP: types.Word(0x1FE),
+		PC: Timer.Vector(),
+		memVals: map[types.Word]byte{0x1FF: 0x01, 0x1FE: 0x02},
+	})
+	if cpu.InterruptMasterEnable() {
+		t.Error("IME not cleared by dispatch")
+	}
+	if cpu.InterruptFlags() != 0xF1 {
+		t.Errorf("IF incorrect, want: 0xf1, got: 0x%x", cpu.InterruptFlags())
+	}
+}
+
+func TestRegisterInterrupts(t *testing.T) {
+	bus := memory.NewBus()
+	cpu := NewCpu(bus)
+	if err := cpu.RegisterInterrupts(bus); err != nil {
+		t.Fatalf("Unexpected error registering interrupts: %v", err)
+	}
+	program := []byte{
+		0x3E, 0x14, // LD A,$14
+		0xE0, 0xFF, // LDH ($FFFF),A
+		0x3E, 0x04, // LD A,$04
+		0xE0, 0x0F, // LDH ($FF0F),A
+		0xF0, 0x0F, // LDH A,($FF0F)
+	}
+	for offset, val := range program {
+		bus.Set(memory.WorkRamStart + types.Word(offset), val)
+	}
+	cpu.PC.Assign(memory.WorkRamStart)
+	for i := 0; i < 5; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatalf("Error stepping cpu: %v", err)
+		}
+	}
+	if cpu.InterruptEnable() != 0x14 {
+		t.Errorf("IE incorrect, want: 0x14, got: 0x%x", cpu.InterruptEnable())
+	}
+	// The unused bits of IF read as 1
+	if cpu.A.Retrieve() != 0xE4 {
+		t.Errorf("Register A incorrect, want: 0xe4, got: 0x%x", cpu.A.Retrieve())
+	}
+	cpu.RequestInterrupt(VBlank)
+	if val, _ := bus.Get(InterruptFlagAddress); val != 0xE5 {
+		t.Errorf("IF incorrect on the bus, want: 0xe5, got: 0x%x", val)
+	}
+	if err := cpu.RegisterInterrupts(bus); err == nil {
+		t.Errorf("Expected an error registering interrupts twice")
+	}
+}
+
+func TestInterruptMasterEnableOpCodes(t *testing.T) {
+	cpu, _ := setupCpuWithState(SystemState{
+		SP: types.Word(0x1FE),
+		PC: types.Word(0x100),
+		memVals: map[types.Word]byte{
+			0x100: 0xF3, // DI
+			0x101: 0x00, // NOP
+			0x102: 0xD9, // RETI
+			0x1FE: 0x00,
+			0x1FF: 0x03,
+		},
+	})
+	cpu.SetInterruptMasterEnable(true)
+	cpu.SetInterruptEnable(0x01)
+
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cpu.InterruptMasterEnable() {
+		t.Fatal("IME not cleared by DI")
+	}
+
+	// Nothing is dispatched with IME clear
+	cpu.RequestInterrupt(VBlank)
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cpu.PC.Retrieve() != types.Word(0x102) {
+		t.Fatalf("Interrupt dispatched with IME clear, PC: %s", cpu.PC.Retrieve())
+	}
+
+	// RETI enables interrupts without EI's delay
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if !cpu.InterruptMasterEnable() {
+		t.Fatal("IME not set by RETI")
+	}
+	if cpu.PC.Retrieve() != types.Word(0x300) {
+		t.Fatalf("RETI returned to wrong address, want: 0x0300, got: %s", cpu.PC.Retrieve())
+	}
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cpu.PC.Retrieve() != VBlank.Vector() {
+		t.Errorf("Interrupt not dispatched after RETI, want PC: %s, got: %s", VBlank.Vector(), cpu.PC.Retrieve())
+	}
+}
+
+func TestHalt(t *testing.T) {
+	testCases := []struct{
+		name string
+		ime bool
+		expectedPC types.Word
+	} {
+		{
+			name: "Wakes and services interrupt with IME set",
+			ime: true,
+			expectedPC: Serial.Vector(),
+		}, {
+			name: "Wakes without servicing interrupt with IME clear",
+			ime: false,
+			// The step that wakes the CPU goes on to run the NOP
+			expectedPC: types.Word(0x102),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cpu, _ := setupCpuWithState(SystemState{
+				SP: types.Word(0x200),
+				PC: types.Word(0x100),
+				memVals: map[types.Word]byte{
+					0x100: 0x76, // HALT
+					0x101: 0x00, // NOP
+				},
+			})
+			cpu.SetInterruptMasterEnable(tc.ime)
+			cpu.SetInterruptEnable(1 << Serial)
+
+			if _, err := cpu.Step(); err != nil {
+				t.Fatalf("Error stepping cpu: %v", err)
+			}
+			if !cpu.Halted() {
+				t.Fatal("CPU not halted")
+			}
+
+			// The clock keeps running while halted
+			for i := 0; i < 3; i++ {
+				cycles, err := cpu.Step()
+				if err != nil {
+					t.Fatalf("Error stepping cpu: %v", err)
+				}
+				if cycles != 4 {
+					t.Errorf("Incorrect number of cycles while halted, want: 4, got: %d", cycles)
+				}
+			}
+			if cpu.PC.Retrieve() != types.Word(0x101) {
+				t.Errorf("PC moved while halted, want: 0x0101, got: %s", cpu.PC.Retrieve())
+			}
+			if cpu.Cycles() != 16 {
+				t.Errorf("Incorrect total cycles, want: 16, got: %d", cpu.Cycles())
+			}
+
+			// A requested but disabled interrupt doesn't wake the CPU
+			cpu.RequestInterrupt(Timer)
+			if _, err := cpu.Step(); err != nil {
+				t.Fatalf("Error stepping cpu: %v", err)
+			}
+			if !cpu.Halted() {
+				t.Fatal("CPU woken by disabled interrupt")
+			}
+
+			cpu.RequestInterrupt(Serial)
+			if _, err := cpu.Step(); err != nil {
+				t.Fatalf("Error stepping cpu: %v", err)
+			}
+			if cpu.Halted() {
+				t.Error("CPU still halted with pending interrupt")
+			}
+			if cpu.PC.Retrieve() != tc.expectedPC {
+				t.Errorf("Incorrect PC after waking, want: %s, got: %s", tc.expectedPC, cpu.PC.Retrieve())
+			}
+		})
+	}
+}
+
+func TestHaltBug(t *testing.T) {
+	cpu, _ := setupCpuWithState(SystemState{
+		PC: types.Word(0x100),
+		memVals: map[types.Word]byte{
+			0x100: 0x76, // HALT
+			0x101: 0x3E, // LD A,d8
+			0x102: 0x14, // INC D
+		},
+	})
+	cpu.SetInterruptEnable(1 << VBlank)
+	cpu.RequestInterrupt(VBlank)
+
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cpu.Halted() {
+		t.Fatal("CPU halted with IME clear and an interrupt pending")
+	}
+
+	// LD A,d8 reads its own opcode as the operand
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cpu.A.Retrieve() != 0x3E {
+		t.Errorf("Register A incorrect, want: 0x3e, got: 0x%x", cpu.A.Retrieve())
+	}
+	if cpu.PC.Retrieve() != types.Word(0x102) {
+		t.Errorf("Incorrect PC, want: 0x0102, got: %s", cpu.PC.Retrieve())
+	}
+
+	// And everything is back to normal afterwards
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cpu.D.Retrieve() != 0x01 {
+		t.Errorf("Register D incorrect, want: 0x1, got: 0x%x", cpu.D.Retrieve())
+	}
+	if cpu.PC.Retrieve() != types.Word(0x103) {
+		t.Errorf("Incorrect PC, want: 0x0103, got: %s", cpu.PC.Retrieve())
+	}
+}
+
+func TestHaltBug_interruptDispatch(t *testing.T) {
+	cpu, mem := setupCpuWithState(SystemState{
+		SP: types.Word(0x200),
+		PC: types.Word(0x100),
+		memVals: map[types.Word]byte{
+			0x100: 0xFB, // EI
+			0x101: 0x76, // HALT
+			0x040: 0x04, // INC B
+			0x041: 0xD9, // RETI
+		},
+	})
+	cpu.SetInterruptEnable(1 << VBlank)
+	cpu.RequestInterrupt(VBlank)
+
+	// EI, then HALT hits the bug as IME is still clear
+	for i := 0; i < 2; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatalf("Error stepping cpu: %v", err)
+		}
+	}
+	if cpu.Halted() {
+		t.Fatal("CPU halted with an interrupt pending")
+	}
+
+	// The interrupt returns to the HALT
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	checkCpuState(t, cpu, mem, SystemState{
+		SP: types.Word(0x1FE),
+		PC: VBlank.Vector(),
+		memVals: map[types.Word]byte{0x1FF: 0x01, 0x1FE: 0x01},
+	})
+
+	// And the handler's first instruction only runs once
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cpu.B.Retrieve() != 0x01 || cpu.PC.Retrieve() != types.Word(0x41) {
+		t.Errorf("Handler's first instruction repeated, B: 0x%x, PC: %s", cpu.B.Retrieve(), cpu.PC.Retrieve())
+	}
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cpu.PC.Retrieve() != types.Word(0x101) {
+		t.Errorf("Incorrect PC after RETI, want: 0x0101, got: %s", cpu.PC.Retrieve())
+	}
+}
+
+func TestStop(t *testing.T) {
+	cpu, _ := setupCpuWithState(SystemState{
+		PC: types.Word(0x100),
+		memVals: map[types.Word]byte{
+			0x100: 0x10, // STOP 0
+			0x101: 0x00,
+			0x102: 0x04, // INC B
+		},
+	})
+	cpu.SetInterruptEnable(0x1F)
+
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if !cpu.Stopped() {
+		t.Fatal("CPU not stopped")
+	}
+
+	// Only the joypad wakes the CPU from STOP
+	cpu.RequestInterrupt(VBlank)
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if !cpu.Stopped() {
+		t.Fatal("CPU woken from STOP by VBlank")
+	}
+
+	cpu.RequestInterrupt(Joypad)
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	if cpu.Stopped() {
+		t.Fatal("CPU not woken from STOP by joypad")
+	}
+	if cpu.B.Retrieve() != 0x01 || cpu.PC.Retrieve() != types.Word(0x103) {
+		t.Errorf("Execution didn't resume after STOP, B: 0x%x, PC: %s", cpu.B.Retrieve(), cpu.PC.Retrieve())
+	}
+}
+
+func TestIllegalOpCodes(t *testing.T) {
+	cpu, _ := setupCpu()
+	for _, code := range []byte{0xD3, 0xDB, 0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC, 0xFD} {
+		if op, exists := cpu.codes[code]; !exists {
+			t.Errorf("Could not find illegal opcode 0x%02x", code)
+		} else if op.Name() != "ILLEGAL" {
+			t.Errorf("Code name incorrect for 0x%02x, want: ILLEGAL, got: %s", code, op.Name())
+		}
+	}
+
+	setupIllegal := func(policy IllegalOpCodePolicy) *Cpu {
+		cpu, _ := setupCpuWithState(SystemState{
+			PC: types.Word(0x100),
+			memVals: map[types.Word]byte{
+				0x100: 0xDD,
+				0x101: 0x04, // INC B
+			},
+		})
+		cpu.SetIllegalOpCodePolicy(policy)
+		return cpu
+	}
+
+	t.Run("Lock up", func(t *testing.T) {
+		cpu := setupIllegal(LockUpOnIllegal)
+		// Even a pending interrupt can't get out of it
+		cpu.SetInterruptMasterEnable(true)
+		cpu.SetInterruptEnable(0x1F)
+		for i := 0; i < 3; i++ {
+			cycles, err := cpu.Step()
+			if err != nil {
+				t.Fatalf("Error stepping cpu: %v", err)
+			}
+			if cycles != 4 {
+				t.Errorf("Incorrect number of cycles, want: 4, got: %d", cycles)
+			}
+			cpu.RequestInterrupt(VBlank)
+		}
+		if !cpu.Locked() {
+			t.Error("CPU not locked")
+		}
+		if cpu.PC.Retrieve() != types.Word(0x100) || cpu.B.Retrieve() != 0 {
+			t.Errorf("CPU kept executing after locking, PC: %s, B: 0x%x", cpu.PC.Retrieve(), cpu.B.Retrieve())
+		}
+		if cpu.Cycles() != 12 {
+			t.Errorf("Incorrect total cycles, want: 12, got: %d", cpu.Cycles())
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		cpu := setupIllegal(ErrorOnIllegal)
+		_, err := cpu.Step()
+		illegalErr, ok := err.(*IllegalOpCodeError)
+		if !ok {
+			t.Fatalf("Error was of incorrect type, want: *IllegalOpCodeError, got: %T", err)
+		}
+		if illegalErr.PC != types.Word(0x100) || illegalErr.Code != 0xDD {
+			t.Errorf("Incorrect error contents, want: 0xDD at 0x0100, got: %v", illegalErr)
+		}
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		cpu := setupIllegal(BreakOnIllegal)
+		var breakPC types.Word
+		var breakCode byte
+		cpu.SetBreakHandler(func(c *Cpu, pc types.Word, code byte) {
+			breakPC = pc
+			breakCode = code
+			// Skip over it like a debugger might
+			c.PC.Assign(pc + 1)
+		})
+		if _, err := cpu.Step(); err != nil {
+			t.Fatalf("Error stepping cpu: %v", err)
+		}
+		if breakPC != types.Word(0x100) || breakCode != 0xDD {
+			t.Errorf("Incorrect break, want: 0xDD at 0x0100, got: 0x%02X at %s", breakCode, breakPC)
+		}
+		if _, err := cpu.Step(); err != nil {
+			t.Fatalf("Error stepping cpu: %v", err)
+		}
+		if cpu.B.Retrieve() != 0x01 {
+			t.Errorf("Execution didn't resume after break, B: 0x%x", cpu.B.Retrieve())
+		}
+	})
+}
+
+func TestCycleAccurateTicks(t *testing.T) {
+	runOpCode := func(t *testing.T, program []byte, flags byte) {
+		mem := memory.SetupBlankMemory(0x10000)
+		cpu := NewCpu(mem)
+		cpu.SetCycleAccurate(true)
+		ticked := 0
+		cpu.SetTicker(func(cycles int) {
+			if cycles != 4 {
+				t.Errorf("Ticked by %d cycles for 0x%02x, want whole M-cycles", cycles, program)
+			}
+			ticked += cycles
+		})
+		cpu.F.Assign(flags)
+		cpu.HL.Assign(types.Word(0xC000))
+		cpu.SP.Assign(types.Word(0xFFF0))
+		cpu.PC.Assign(types.Word(0x100))
+		for offset, val := range program {
+			mem.Set(types.Word(0x100 + offset), val)
+		}
+		cycles, err := cpu.Step()
+		if err != nil {
+			t.Fatalf("Error stepping cpu for 0x%02x: %v", program, err)
+		}
+		if ticked != cycles {
+			t.Errorf("Incorrect ticks for 0x%02x, want: %d, got: %d", program, cycles, ticked)
+		}
+	}
+
+	cpu, _ := setupCpu()
+	for code, op := range cpu.codes {
+		if _, illegal := op.(*IllegalOpCode); illegal {
+			continue
+		}
+		for _, flags := range []byte{0x00, 0xF0} {
+			runOpCode(t, []byte{code, 0x00, 0x00}, flags)
+		}
+	}
+	for code := range cpu.cbCodes {
+		runOpCode(t, []byte{0xCB, code}, 0x00)
+	}
+}
+
+func TestCycleAccurateAccessTiming(t *testing.T) {
+	testCases := []struct{
+		name string
+		program []byte
+		// M-cycle in which each address is written, counting the opcode fetch as 1
+		writes map[types.Word]int
+	} {
+		{
+			name: "LD (HL),A",
+			program: []byte{0x77},
+			writes: map[types.Word]int{0xC000: 2},
+		}, {
+			name: "PUSH BC",
+			program: []byte{0xC5},
+			writes: map[types.Word]int{0xFFEF: 3, 0xFFEE: 4},
+		}, {
+			name: "CALL a16",
+			program: []byte{0xCD, 0x00, 0x02},
+			writes: map[types.Word]int{0xFFEF: 5, 0xFFEE: 6},
+		}, {
+			name: "RST 08H",
+			program: []byte{0xCF},
+			writes: map[types.Word]int{0xFFEF: 3, 0xFFEE: 4},
+		}, {
+			name: "LD (a16),SP",
+			program: []byte{0x08, 0x00, 0xC1},
+			writes: map[types.Word]int{0xC100: 4, 0xC101: 5},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.SetupBlankMemory(0x10000)
+			cpu := NewCpu(mem)
+			cpu.SetCycleAccurate(true)
+			cpu.A.Assign(0xAA)
+			cpu.BC.Assign(types.Word(0xBBBB))
+			cpu.HL.Assign(types.Word(0xC000))
+			cpu.SP.Assign(types.Word(0xFFF0))
+			cpu.PC.Assign(types.Word(0x100))
+			for offset, val := range tc.program {
+				mem.Set(types.Word(0x100 + offset), val)
+			}
+
+			// Each tick happens just before the access in that M-cycle, so
+			// anything visible at tick n was written in an earlier M-cycle
+			writtenBy := make(map[types.Word]int)
+			mCycle := 0
+			checkWrites := func() {
+				for address := range tc.writes {
+					val, _ := mem.Get(address)
+					if _, seen := writtenBy[address]; !seen && val != 0 {
+						writtenBy[address] = mCycle
+					}
+				}
+			}
+			cpu.SetTicker(func(cycles int) {
+				checkWrites()
+				mCycle++
+			})
+			if _, err := cpu.Step(); err != nil {
+				t.Fatalf("Error stepping cpu: %v", err)
+			}
+			checkWrites()
+
+			for address, expected := range tc.writes {
+				if writtenBy[address] != expected {
+					t.Errorf("Write to %s in wrong M-cycle, want: %d, got: %d", address, expected, writtenBy[address])
+				}
+			}
+		})
+	}
+}
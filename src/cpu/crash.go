@@ -0,0 +1,143 @@
+package cpu
+
+import (
+	"fmt"
+	"strings"
+	"types"
+)
+
+// Instructions kept for crash dumps unless SetHistorySize says otherwise
+const defaultHistorySize = 32
+
+// Bytes of memory shown either side of PC in a crash dump, and above SP
+const crashDumpWindow = 16
+
+type executedInstruction struct {
+	PC types.Word
+	// Opcode and operands as they were when the instruction ran
+	bytes [3]byte
+}
+
+// An instruction from the history, oldest first in a CrashDump
+type ExecutedInstruction struct {
+	PC types.Word
+	Bytes []byte
+	Name string
+}
+
+// A slice of memory starting at Start
+type MemoryWindow struct {
+	Start types.Word
+	Bytes []byte
+}
+
+// State of the CPU when Step failed
+type CrashDump struct {
+	Err error
+	Registers Registers
+	Cycles uint64
+	// The last instruction is the one that failed
+	History []ExecutedInstruction
+	AroundPC MemoryWindow
+	Stack MemoryWindow
+}
+
+// Number of executed instructions to keep for crash dumps. 0 turns the
+// history off
+func (c *Cpu) SetHistorySize(size int) {
+	c.history = make([]executedInstruction, 0, size)
+	c.historyNext = 0
+}
+
+// Dump from the last time Step returned an emulation error, nil if it never
+// has
+func (c *Cpu) LastCrash() *CrashDump {
+	return c.crashDump
+}
+
+// Records an error from somewhere that can't return one. Only the first
+// error in a Step is kept
+func (c *Cpu) fault(err error) {
+	if c.faultErr == nil {
+		c.faultErr = err
+	}
+}
+
+func (c *Cpu) recordHistory(pc types.Word) {
+	if cap(c.history) == 0 {
+		return
+	}
+	entry := executedInstruction{PC: pc}
+	for i := range entry.bytes {
+		entry.bytes[i] = c.peekByte(pc + types.Word(i))
+	}
+	if len(c.history) < cap(c.history) {
+		c.history = append(c.history, entry)
+		return
+	}
+	c.history[c.historyNext] = entry
+	c.historyNext = (c.historyNext + 1) % len(c.history)
+}
+
+// Takes a crash dump for err and hands err back for Step to return
+func (c *Cpu) crash(err error) error {
+	dump := &CrashDump{
+		Err: err,
+		Registers: c.Registers(),
+		Cycles: c.cycles,
+		AroundPC: c.memoryWindow(c.PC.Retrieve() - crashDumpWindow, 2 * crashDumpWindow),
+		Stack: c.memoryWindow(c.SP.Retrieve(), crashDumpWindow),
+	}
+	for i := range c.history {
+		entry := c.history[(c.historyNext + i) % len(c.history)]
+		dump.History = append(dump.History, c.describe(entry))
+	}
+	c.crashDump = dump
+	return err
+}
+
+func (c *Cpu) memoryWindow(start types.Word, size int) MemoryWindow {
+	window := MemoryWindow{Start: start}
+	for i := 0; i < size; i++ {
+		window.Bytes = append(window.Bytes, c.peekByte(start + types.Word(i)))
+	}
+	return window
+}
+
+// Names a recorded instruction and trims its bytes to the opcode's length
+func (c *Cpu) describe(entry executedInstruction) ExecutedInstruction {
+	op, exists := c.codes[entry.bytes[0]]
+	if entry.bytes[0] == 0xCB {
+		op, exists = c.cbCodes[entry.bytes[1]]
+	}
+	if !exists {
+		return ExecutedInstruction{PC: entry.PC, Bytes: entry.bytes[:1], Name: "UNKNOWN"}
+	}
+	return ExecutedInstruction{PC: entry.PC, Bytes: entry.bytes[:op.Length()], Name: op.Name()}
+}
+
+func (w MemoryWindow) String() string {
+	var lines []string
+	for row := 0; row < len(w.Bytes); row += 16 {
+		end := row + 16
+		if end > len(w.Bytes) {
+			end = len(w.Bytes)
+		}
+		lines = append(lines, fmt.Sprintf("  %04X: % X", uint16(w.Start) + uint16(row), w.Bytes[row:end]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (d *CrashDump) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Crash: %v\n", d.Err)
+	fmt.Fprintf(&b, "Registers: %s\n", d.Registers)
+	fmt.Fprintf(&b, "Cycles: %d\n", d.Cycles)
+	fmt.Fprintf(&b, "Last %d instructions:\n", len(d.History))
+	for _, inst := range d.History {
+		fmt.Fprintf(&b, "  %04X: %-8s  %s\n", uint16(inst.PC), fmt.Sprintf("% X", inst.Bytes), inst.Name)
+	}
+	fmt.Fprintf(&b, "Memory around PC:\n%s\n", d.AroundPC)
+	fmt.Fprintf(&b, "Stack:\n%s\n", d.Stack)
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package cpu
+
+import (
+	"memory"
+	"strings"
+	"testing"
+	"types"
+)
+
+// Calls GetFlag with a flag that doesn't exist
+type badFlagOpCode struct {
+	BaseOpCode
+}
+
+func (b *badFlagOpCode) Run(cpu *Cpu) (int, bool, error) {
+	cpu.GetFlag(42)
+	return 4, false, nil
+}
+
+func (b *badFlagOpCode) Name() string {
+	return "BAD FLAG"
+}
+
+func TestCrashDump(t *testing.T) {
+	cpu, mem := setupCpu()
+	cpu.PC.Assign(types.Word(0x100))
+	cpu.SP.Assign(types.Word(0x200))
+	cpu.SetHistorySize(3)
+	// Four NOPs, LD B,d8, then an unknown opcode
+	delete(cpu.codes, 0xD3)
+	for offset, val := range []byte{0x00, 0x00, 0x00, 0x00, 0x06, 0x12, 0xD3} {
+		mem.Set(types.Word(0x100 + offset), val)
+	}
+
+	var err error
+	for steps := 0; steps < 10 && err == nil; steps++ {
+		_, err = cpu.Step()
+	}
+	if _, ok := err.(*UnknownOpCodeError); !ok {
+		t.Fatalf("Expected an UnknownOpCodeError, got %v", err)
+	}
+	dump := cpu.LastCrash()
+	if dump == nil {
+		t.Fatalf("Expected a crash dump")
+	}
+	if dump.Err != err || dump.Registers.PC != 0x106 || dump.Registers.B != 0x12 {
+		t.Errorf("Unexpected crash dump state: %v, %s", dump.Err, dump.Registers)
+	}
+
+	// Only the last three instructions are kept, ending with the bad one
+	expected := []ExecutedInstruction{
+		{PC: 0x103, Bytes: []byte{0x00}, Name: "NOP"},
+		{PC: 0x104, Bytes: []byte{0x06, 0x12}, Name: "LD B,d8"},
+		{PC: 0x106, Bytes: []byte{0xD3}, Name: "UNKNOWN"},
+	}
+	if len(dump.History) != len(expected) {
+		t.Fatalf("Expected %d instructions of history, got %v", len(expected), dump.History)
+	}
+	for i, want := range expected {
+		got := dump.History[i]
+		if got.PC != want.PC || string(got.Bytes) != string(want.Bytes) || got.Name != want.Name {
+			t.Errorf("History %d: want %+v, got %+v", i, want, got)
+		}
+	}
+
+	if dump.AroundPC.Start != 0x106 - crashDumpWindow || dump.AroundPC.Bytes[crashDumpWindow] != 0xD3 {
+		t.Errorf("Expected memory around PC to be centered on the bad opcode, got %+v", dump.AroundPC)
+	}
+	text := dump.String()
+	for _, line := range []string{
+		"Crash: Unknown opcode 0xD3",
+		"Last 3 instructions:",
+		"  0104: 06 12     LD B,d8",
+		"  0106: D3 00 00",
+	} {
+		if !strings.Contains(text, line) {
+			t.Errorf("Expected crash dump to contain %q, got:\n%s", line, text)
+		}
+	}
+}
+
+func TestStep_faults(t *testing.T) {
+	t.Run("bus fault", func(t *testing.T) {
+		cpu, mem := setupCpu()
+		// LD A,(a16) from past the end of memory
+		mem.Set(0x00, 0xFA)
+		mem.Set(0x01, 0x00)
+		mem.Set(0x02, 0xC0)
+		_, err := cpu.Step()
+		busErr, ok := err.(*memory.BusError)
+		if !ok || busErr.Address != 0xC000 || busErr.Write {
+			t.Fatalf("Expected a bus fault reading 0xC000, got %v", err)
+		}
+		if cpu.LastCrash() == nil || cpu.LastCrash().Err != err {
+			t.Errorf("Expected a crash dump for the bus fault")
+		}
+	})
+
+	t.Run("invalid flag", func(t *testing.T) {
+		cpu, _ := setupCpu()
+		cpu.codes[0x00] = &badFlagOpCode{BaseOpCode{code: 0x00, length: 1}}
+		_, err := cpu.Step()
+		if flagErr, ok := err.(*InvalidFlagError); !ok || flagErr.Flag != 42 {
+			t.Fatalf("Expected an InvalidFlagError, got %v", err)
+		}
+		// The fault doesn't stick to the next instruction
+		cpu.codes[0x00] = &NoOpCode{BaseOpCode{code: 0x00, length: 1}}
+		if _, err := cpu.Step(); err != nil {
+			t.Errorf("Unexpected error after fault: %v", err)
+		}
+	})
+
+	t.Run("invalid bit", func(t *testing.T) {
+		cpu, _ := setupCpu()
+		if err := cpu.B.SetBit(8, true); err == nil {
+			t.Errorf("Expected an error setting bit 8")
+		}
+		if _, err := cpu.B.GetBit(9); err == nil {
+			t.Errorf("Expected an error getting bit 9")
+		}
+		if cpu.B.Retrieve() != 0 {
+			t.Errorf("Expected B to be untouched, got 0x%02X", cpu.B.Retrieve())
+		}
+	})
+}
@@ -0,0 +1,384 @@
+// Generates the CPU's opcode tables from the instruction spec. Run through
+// go generate in the cpu package.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"strings"
+)
+
+type instructionSpec struct {
+	Code string `json:"code"`
+	Mnemonic string `json:"mnemonic"`
+	Length int `json:"length"`
+	Cycles int `json:"cycles"`
+	CyclesNotTaken int `json:"cycles_not_taken"`
+	Flags string `json:"flags"`
+}
+
+type spec struct {
+	Unprefixed []instructionSpec `json:"unprefixed"`
+	CBPrefixed []instructionSpec `json:"cbprefixed"`
+}
+
+var (
+	specPath = flag.String("spec", "instructions.json", "Instruction spec to read")
+	outPath = flag.String("out", "opcode_table.go", "Go file to write")
+)
+
+var registers8Bit = map[string]bool{
+	"A": true, "B": true, "C": true, "D": true, "E": true, "H": true, "L": true,
+}
+
+var registers16Bit = map[string]bool{
+	"AF": true, "BC": true, "DE": true, "HL": true, "SP": true,
+}
+
+var conditions = map[string]string{
+	"NZ": "NotZero",
+	"Z": "Zero",
+	"NC": "NoCarry",
+	"C": "Carry",
+}
+
+// Anything that can be used as a ByteSource
+func byteSource(operand string) (string, error) {
+	switch {
+	case registers8Bit[operand]:
+		return "&c." + operand, nil
+	case operand == "(HL)":
+		return "&c.HL", nil
+	case operand == "d8":
+		return "&ImmediateByte{pc: &c.PC}", nil
+	case operand == "(a8)":
+		return "&HighPageAddress{pc: &c.PC}", nil
+	case operand == "(C)":
+		return "&HighPageAddress{pc: &c.PC, offset: &c.C}", nil
+	case operand == "(a16)":
+		return "&ImmediateAddress{pc: &c.PC}", nil
+	}
+	return "", fmt.Errorf("Not a byte source: %s", operand)
+}
+
+func register16Bit(operand string) (string, error) {
+	if !registers16Bit[operand] {
+		return "", fmt.Errorf("Not a 16 bit register: %s", operand)
+	}
+	return "&c." + operand, nil
+}
+
+// Register pair addressing memory, with the optional post increment/decrement
+func memoryRegister(operand string) (register string, modifier string, ok bool) {
+	if !strings.HasPrefix(operand, "(") || !strings.HasSuffix(operand, ")") {
+		return "", "", false
+	}
+	inner := strings.Trim(operand, "()")
+	if strings.HasSuffix(inner, "+") || strings.HasSuffix(inner, "-") {
+		modifier = inner[len(inner)-1:]
+		inner = inner[:len(inner)-1]
+	}
+	if !registers16Bit[inner] {
+		return "", "", false
+	}
+	return "&c." + inner, modifier, true
+}
+
+// Splits off a leading condition, e.g. "NZ,a16" -> "NotZero", "a16"
+func condition(operands []string) string {
+	if len(operands) > 0 {
+		if cond, ok := conditions[operands[0]]; ok {
+			return cond
+		}
+	}
+	return "Always"
+}
+
+// Go expression building the opcode for an instruction, minus the BaseOpCode
+// which is filled in by the caller. Returns the type name and its fields.
+func opCodeFields(mnemonic string, cb bool) (string, []string, error) {
+	op := mnemonic
+	var operands []string
+	if space := strings.Index(mnemonic, " "); space >= 0 {
+		op = mnemonic[:space]
+		operands = strings.Split(mnemonic[space+1:], ",")
+	}
+	unsupported := fmt.Errorf("Unsupported instruction: %s", mnemonic)
+	source := func(index int) (string, error) {
+		if index >= len(operands) {
+			return "", unsupported
+		}
+		return byteSource(operands[index])
+	}
+
+	if cb {
+		switch op {
+		case "RLC", "RRC", "RL", "RR":
+			src, err := source(0)
+			direction := "Left"
+			if op[1] == 'R' {
+				direction = "Right"
+			}
+			return "RotateOpCode", []string{
+				"r1: " + src,
+				"direction: " + direction,
+				fmt.Sprintf("circular: %t", strings.HasSuffix(op, "C")),
+				"isCB: true",
+			}, err
+		case "SLA":
+			src, err := source(0)
+			return "ShiftOpCode", []string{"r1: " + src, "direction: Left"}, err
+		case "SRA", "SRL":
+			src, err := source(0)
+			return "ShiftOpCode", []string{
+				"r1: " + src,
+				"direction: Right",
+				fmt.Sprintf("arithmetic: %t", op == "SRA"),
+			}, err
+		case "SWAP":
+			src, err := source(0)
+			return "SwapOpCode", []string{"r1: " + src}, err
+		case "BIT", "RES", "SET":
+			src, err := source(1)
+			fields := []string{"r1: " + src, "bit: " + operands[0]}
+			if op == "BIT" {
+				return "TestBitOpCode", fields, err
+			}
+			return "SetBitOpCode", append(fields, fmt.Sprintf("value: %t", op == "SET")), err
+		}
+		return "", nil, unsupported
+	}
+
+	switch op {
+	case "NOP":
+		return "NoOpCode", nil, nil
+	case "HALT":
+		return "HaltOpCode", nil, nil
+	case "STOP":
+		return "StopOpCode", nil, nil
+	case "DI", "EI":
+		return "InterruptMasterOpCode", []string{fmt.Sprintf("enable: %t", op == "EI")}, nil
+	case "PREFIX":
+		return "CBPrefixOpCode", nil, nil
+	case "ILLEGAL":
+		return "IllegalOpCode", nil, nil
+	case "DAA":
+		return "DecimalAdjustOpCode", []string{"r1: &c.A"}, nil
+	case "CPL":
+		return "ComplementOpCode", []string{"r1: &c.A"}, nil
+	case "SCF", "CCF":
+		return "CarryFlagOpCode", []string{fmt.Sprintf("complement: %t", op == "CCF")}, nil
+	case "RLCA", "RRCA", "RLA", "RRA":
+		direction := "Left"
+		if op[1] == 'R' {
+			direction = "Right"
+		}
+		return "RotateOpCode", []string{
+			"r1: &c.A",
+			"direction: " + direction,
+			fmt.Sprintf("circular: %t", op[2] == 'C'),
+			"isCB: false",
+		}, nil
+	case "LD", "LDH":
+		return loadFields(op, operands, unsupported)
+	case "INC", "DEC":
+		mod := "Increment"
+		opType := "Inc8BitRegOpCode"
+		if op == "DEC" {
+			mod = "Decrement"
+			opType = "Dec8BitRegOpCode"
+		}
+		if registers16Bit[operands[0]] {
+			return "IncDec16Bit", []string{"target: &c." + operands[0], "mod: " + mod}, nil
+		}
+		src, err := source(0)
+		return opType, []string{"r1: " + src}, err
+	case "ADD", "ADC":
+		if operands[0] == "HL" {
+			reg, err := register16Bit(operands[1])
+			return "Add16BitRegOpCode", []string{"r1: &c.HL", "r2: " + reg}, err
+		}
+		if operands[0] == "SP" {
+			return "AddSPOffsetOpCode", []string{"r1: &c.SP"}, nil
+		}
+		src, err := source(1)
+		return "Add8BitRegOpCode", []string{
+			"r1: &c.A",
+			"r2: " + src,
+			fmt.Sprintf("includeCarry: %t", op == "ADC"),
+		}, err
+	case "SUB", "SBC":
+		src, err := source(len(operands) - 1)
+		return "Sub8BitRegOpCode", []string{
+			"r1: &c.A",
+			"r2: " + src,
+			fmt.Sprintf("includeCarry: %t", op == "SBC"),
+		}, err
+	case "AND", "XOR", "OR", "CP":
+		src, err := source(0)
+		return "Logical8BitOp", []string{"target: &c.A", "source: " + src, "operation: " + op}, err
+	case "JP":
+		if operands[0] == "(HL)" {
+			return "JumpRegisterOpCode", []string{"r1: &c.HL"}, nil
+		}
+		return "JumpOpCode", []string{"condition: " + condition(operands)}, nil
+	case "JR":
+		return "JumpRelativeOpCode", []string{"condition: " + condition(operands)}, nil
+	case "CALL":
+		return "CallOpCode", []string{"condition: " + condition(operands)}, nil
+	case "RET":
+		return "ReturnOpCode", []string{"condition: " + condition(operands)}, nil
+	case "RETI":
+		return "ReturnOpCode", []string{"condition: Always", "enableInterrupts: true"}, nil
+	case "RST":
+		target, err := strconv.ParseUint(strings.TrimSuffix(operands[0], "H"), 16, 16)
+		return "RestartOpCode", []string{fmt.Sprintf("target: 0x%02X", target)}, err
+	case "PUSH", "POP":
+		reg, err := register16Bit(operands[0])
+		opType := "PushOpCode"
+		if op == "POP" {
+			opType = "PopOpCode"
+		}
+		return opType, []string{"r1: " + reg}, err
+	}
+	return "", nil, unsupported
+}
+
+func loadFields(op string, operands []string, unsupported error) (string, []string, error) {
+	if len(operands) != 2 {
+		return "", nil, unsupported
+	}
+	dst, src := operands[0], operands[1]
+	modifierFields := func(name, modifier string) []string {
+		switch modifier {
+		case "+":
+			return []string{"increment" + name + ": true"}
+		case "-":
+			return []string{"decrement" + name + ": true"}
+		}
+		return nil
+	}
+
+	switch {
+	case op == "LDH":
+		dstSource, err := byteSource(dst)
+		if err != nil {
+			return "", nil, err
+		}
+		srcSource, err := byteSource(src)
+		return "LdByteSourceOpCode", []string{"r1: " + dstSource, "r2: " + srcSource, "highPage: true"}, err
+	case registers8Bit[dst] && registers8Bit[src]:
+		return "Ld8BitRegisterOpCode", []string{"r1: &c." + dst, "r2: &c." + src}, nil
+	case registers8Bit[dst] && src == "d8":
+		return "Ld8BitImmediateOpCode", []string{"r1: &c." + dst}, nil
+	case dst == "(HL)" && src == "d8":
+		return "LdMemoryImmediateOpCode", []string{"r1: &c.HL"}, nil
+	case registers16Bit[dst] && src == "d16":
+		return "Ld16BitImmediateOpCode", []string{"r1: &c." + dst}, nil
+	case dst == "(a16)" && src == "SP":
+		return "LdSPIntoMemOpCode", []string{"r1: &c.SP"}, nil
+	case dst == "HL" && src == "SP+r8":
+		return "LdSPOffsetOpCode", []string{"r1: &c.HL", "r2: &c.SP"}, nil
+	case registers16Bit[dst] && registers16Bit[src]:
+		return "Ld16BitRegisterOpCode", []string{"r1: &c." + dst, "r2: &c." + src}, nil
+	}
+	if reg, modifier, ok := memoryRegister(dst); ok && registers8Bit[src] {
+		return "LdRegIntoMemOpCode", append([]string{"r1: " + reg, "r2: &c." + src}, modifierFields("R1", modifier)...), nil
+	}
+	if reg, modifier, ok := memoryRegister(src); ok && registers8Bit[dst] {
+		return "LdMemIntoRegOpCode", append([]string{"r1: &c." + dst, "r2: " + reg}, modifierFields("R2", modifier)...), nil
+	}
+	// Everything else goes through ByteSources, e.g. LD (C),A
+	dstSource, err := byteSource(dst)
+	if err != nil {
+		return "", nil, unsupported
+	}
+	srcSource, err := byteSource(src)
+	if err != nil {
+		return "", nil, unsupported
+	}
+	return "LdByteSourceOpCode", []string{"r1: " + dstSource, "r2: " + srcSource}, nil
+}
+
+func writeInstructions(buf *bytes.Buffer, name string, instructions []instructionSpec) error {
+	fmt.Fprintf(buf, "var %s = [256]Instruction{\n", name)
+	for _, inst := range instructions {
+		code, err := strconv.ParseUint(inst.Code, 0, 8)
+		if err != nil {
+			return err
+		}
+		notTaken := inst.CyclesNotTaken
+		if notTaken == 0 {
+			notTaken = inst.Cycles
+		}
+		fmt.Fprintf(buf, "\t0x%02X: {Mnemonic: %q, Length: %d, Cycles: %d, CyclesNotTaken: %d, Flags: %q},\n",
+			code, inst.Mnemonic, inst.Length, inst.Cycles, notTaken, inst.Flags)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+	return nil
+}
+
+func writeGenerator(buf *bytes.Buffer, name string, instructions []instructionSpec, cb bool) error {
+	fmt.Fprintf(buf, "func (c *Cpu) %s() map[byte]OpCode {\n", name)
+	fmt.Fprintf(buf, "\tcodes := make(map[byte]OpCode)\n")
+	for _, inst := range instructions {
+		code, err := strconv.ParseUint(inst.Code, 0, 8)
+		if err != nil {
+			return err
+		}
+		opType, fields, err := opCodeFields(inst.Mnemonic, cb)
+		if err != nil {
+			return fmt.Errorf("0x%02X: %v", code, err)
+		}
+		fields = append([]string{fmt.Sprintf("BaseOpCode: BaseOpCode{code: 0x%02X, length: %d}", code, inst.Length)}, fields...)
+		fmt.Fprintf(buf, "\tcodes[0x%02X] = &%s{%s} // %s\n", code, opType, strings.Join(fields, ", "), inst.Mnemonic)
+	}
+	fmt.Fprintf(buf, "\treturn codes\n}\n\n")
+	return nil
+}
+
+func main() {
+	flag.Parse()
+
+	data, err := ioutil.ReadFile(*specPath)
+	if err != nil {
+		log.Fatalf("Error reading spec: %v", err)
+	}
+	var instructions spec
+	if err := json.Unmarshal(data, &instructions); err != nil {
+		log.Fatalf("Error parsing spec: %v", err)
+	}
+	if len(instructions.Unprefixed) != 256 || len(instructions.CBPrefixed) != 256 {
+		log.Fatalf("Spec must describe all 256 opcodes in each table, got %d and %d",
+			len(instructions.Unprefixed), len(instructions.CBPrefixed))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen/main.go from %s. DO NOT EDIT.\n\n", *specPath)
+	fmt.Fprintf(&buf, "package cpu\n\n")
+	steps := []func() error{
+		func() error { return writeInstructions(&buf, "MainInstructions", instructions.Unprefixed) },
+		func() error { return writeInstructions(&buf, "CBInstructions", instructions.CBPrefixed) },
+		func() error { return writeGenerator(&buf, "generateOpCodes", instructions.Unprefixed, false) },
+		func() error { return writeGenerator(&buf, "generateCBOpCodes", instructions.CBPrefixed, true) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			log.Fatalf("Error generating opcodes: %v", err)
+		}
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("Error formatting generated code: %v", err)
+	}
+	if err := ioutil.WriteFile(*outPath, formatted, 0644); err != nil {
+		log.Fatalf("Error writing %s: %v", *outPath, err)
+	}
+}
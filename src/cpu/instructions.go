@@ -0,0 +1,27 @@
+package cpu
+
+//go:generate go run gen/main.go -spec instructions.json -out opcode_table.go
+
+// Static description of an instruction, generated from instructions.json
+// along with the opcode tables themselves
+type Instruction struct {
+	Mnemonic string
+	Length int
+	Cycles int
+	// Cycles when a conditional jump, call or return isn't taken. The same
+	// as Cycles for everything else
+	CyclesNotTaken int
+	// Effect on Z, N, H and C in that order. '-' leaves the flag alone, '0'
+	// and '1' reset and set it, and the flag's letter means it depends on
+	// the result
+	Flags string
+}
+
+func (i Instruction) Conditional() bool {
+	return i.Cycles != i.CyclesNotTaken
+}
+
+// One of the opcodes that don't exist on the hardware
+func (i Instruction) Illegal() bool {
+	return i.Mnemonic == "ILLEGAL"
+}
@@ -0,0 +1,126 @@
+package cpu
+
+import (
+	"memory"
+	"testing"
+)
+
+// Fresh CPU for running a single instruction, with HL and SP pointing
+// somewhere harmless
+func setupInstructionCpu(flags byte) *Cpu {
+	cpu := NewCpu(memory.SetupBlankMemory(0x10000))
+	cpu.HL.Assign(0xC000)
+	cpu.SP.Assign(0xFFF0)
+	cpu.PC.Assign(0x100)
+	cpu.F.Assign(flags)
+	return cpu
+}
+
+func checkInstructionTable(t *testing.T, table string, instructions [256]Instruction, codes map[byte]OpCode) {
+	for code := 0; code < 256; code++ {
+		inst := instructions[code]
+		op, exists := codes[byte(code)]
+		if !exists {
+			t.Errorf("%s 0x%02X: %s missing from opcode table", table, code, inst.Mnemonic)
+			continue
+		}
+		if op.Name() != inst.Mnemonic {
+			t.Errorf("%s 0x%02X: name %s, spec says %s", table, code, op.Name(), inst.Mnemonic)
+		}
+		if op.Length() != inst.Length {
+			t.Errorf("%s 0x%02X: %s length %d, spec says %d", table, code, inst.Mnemonic, op.Length(), inst.Length)
+		}
+	}
+}
+
+func TestInstructionTables(t *testing.T) {
+	cpu := setupInstructionCpu(0x00)
+	checkInstructionTable(t, "main", MainInstructions, cpu.codes)
+	checkInstructionTable(t, "CB", CBInstructions, cpu.cbCodes)
+}
+
+// Runs every opcode with all flags reset and with all flags set, which takes
+// each conditional instruction down both of its paths
+func TestInstructionCycles(t *testing.T) {
+	check := func(table string, instructions [256]Instruction, cb bool) {
+		for code := 0; code < 256; code++ {
+			inst := instructions[code]
+			if inst.Illegal() || (!cb && code == 0xCB) {
+				// Illegal opcodes lock up, and the prefix is run with its CB opcode
+				continue
+			}
+			seen := make(map[int]bool)
+			for _, flags := range []byte{0x00, 0xF0} {
+				cpu := setupInstructionCpu(flags)
+				op := cpu.codes[byte(code)]
+				if cb {
+					op = cpu.cbCodes[byte(code)]
+				}
+				cycles, _, err := op.Run(cpu)
+				if err != nil {
+					t.Errorf("%s 0x%02X: %s returned error: %v", table, code, inst.Mnemonic, err)
+					continue
+				}
+				if cycles != inst.Cycles && cycles != inst.CyclesNotTaken {
+					t.Errorf("%s 0x%02X: %s took %d cycles, spec says %d/%d",
+						table, code, inst.Mnemonic, cycles, inst.Cycles, inst.CyclesNotTaken)
+				}
+				seen[cycles] = true
+			}
+			if inst.Conditional() && (!seen[inst.Cycles] || !seen[inst.CyclesNotTaken]) {
+				t.Errorf("%s 0x%02X: %s didn't take both %d and %d cycles",
+					table, code, inst.Mnemonic, inst.Cycles, inst.CyclesNotTaken)
+			}
+		}
+	}
+	check("main", MainInstructions, false)
+	check("CB", CBInstructions, true)
+}
+
+// Flags the spec says are always reset, always set or left alone
+func TestInstructionFlags(t *testing.T) {
+	flagOrder := []int{Z, N, H, C}
+	check := func(table string, instructions [256]Instruction, cb bool) {
+		for code := 0; code < 256; code++ {
+			inst := instructions[code]
+			if inst.Illegal() || (!cb && code == 0xCB) {
+				continue
+			}
+			for _, flags := range []byte{0x00, 0xF0} {
+				cpu := setupInstructionCpu(flags)
+				original := cpu.F.Retrieve()
+				op := cpu.codes[byte(code)]
+				if cb {
+					op = cpu.cbCodes[byte(code)]
+				}
+				if _, _, err := op.Run(cpu); err != nil {
+					continue
+				}
+				if code == 0xF1 && !cb {
+					// POP AF loads the flags from the stack
+					continue
+				}
+				for index, flag := range flagOrder {
+					got := cpu.GetFlag(flag)
+					var want bool
+					switch inst.Flags[index] {
+					case '-':
+						want = original & (0x80 >> uint(index)) != 0
+					case '0':
+						want = false
+					case '1':
+						want = true
+					default:
+						continue
+					}
+					if got != want {
+						t.Errorf("%s 0x%02X: %s with F=0x%02X left %s %t, spec says %c",
+							table, code, inst.Mnemonic, original, FlagEnumToName(flag), got, inst.Flags[index])
+					}
+				}
+			}
+		}
+	}
+	check("main", MainInstructions, false)
+	check("CB", CBInstructions, true)
+}
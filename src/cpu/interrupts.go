@@ -0,0 +1,155 @@
+package cpu
+
+import (
+	"fmt"
+	"memory"
+	"types"
+)
+
+// Interrupt sources. The value is the bit used in IF and IE, and lower bits
+// take priority when more than one interrupt is pending.
+type Interrupt uint
+
+const (
+	VBlank Interrupt = iota
+	LCDStat
+	Timer
+	Serial
+	Joypad
+)
+
+const (
+	InterruptFlagAddress = types.Word(0xFF0F)
+	InterruptEnableAddress = types.Word(0xFFFF)
+
+	// Only the bottom 5 bits of IF and IE are used
+	interruptMask = byte(0x1F)
+	// Pushing PC and jumping to the vector takes 5 M-cycles
+	interruptDispatchCycles = 20
+)
+
+func (i Interrupt) String() string {
+	switch i {
+	case VBlank:
+		return "VBlank"
+	case LCDStat:
+		return "LCD STAT"
+	case Timer:
+		return "Timer"
+	case Serial:
+		return "Serial"
+	case Joypad:
+		return "Joypad"
+	default:
+		return fmt.Sprintf("Unknown interrupt: %d", uint(i))
+	}
+}
+
+// Address the CPU jumps to when servicing the interrupt
+func (i Interrupt) Vector() types.Word {
+	return types.Word(0x40 + 8 * uint16(i))
+}
+
+// Marks an interrupt as pending in IF. This is how other components (PPU,
+// timer, serial, joypad) signal the CPU.
+func (c *Cpu) RequestInterrupt(interrupt Interrupt) {
+	c.interruptFlags |= 1 << interrupt
+}
+
+// IF (0xFF0F). The unused upper bits always read as 1
+func (c *Cpu) InterruptFlags() byte {
+	return c.interruptFlags | ^interruptMask
+}
+
+func (c *Cpu) SetInterruptFlags(value byte) {
+	c.interruptFlags = value & interruptMask
+}
+
+// IE (0xFFFF). Unlike IF all 8 bits can be read back
+func (c *Cpu) InterruptEnable() byte {
+	return c.interruptEnable
+}
+
+func (c *Cpu) SetInterruptEnable(value byte) {
+	c.interruptEnable = value
+}
+
+// IF and IE as seen by the program through the bus
+type interruptRegisters struct {
+	cpu *Cpu
+}
+
+func (r interruptRegisters) Read(address types.Word) byte {
+	if address == InterruptFlagAddress {
+		return r.cpu.interruptFlags
+	}
+	return r.cpu.interruptEnable
+}
+
+func (r interruptRegisters) Write(address types.Word, value byte) {
+	if address == InterruptFlagAddress {
+		r.cpu.SetInterruptFlags(value)
+	} else {
+		r.cpu.SetInterruptEnable(value)
+	}
+}
+
+// Maps IF and IE onto the bus, so that loads and stores at 0xFF0F and 0xFFFF
+// reach the interrupt controller
+func (c *Cpu) RegisterInterrupts(bus *memory.Bus) error {
+	registers := interruptRegisters{c}
+	if err := bus.RegisterDevice(InterruptFlagAddress, InterruptFlagAddress, registers); err != nil {
+		return err
+	}
+	if err := bus.SetReadMask(InterruptFlagAddress, ^interruptMask); err != nil {
+		return err
+	}
+	return bus.RegisterDevice(InterruptEnableAddress, InterruptEnableAddress, registers)
+}
+
+// IME, the master switch set by EI and RETI and cleared by DI
+func (c *Cpu) InterruptMasterEnable() bool {
+	return c.ime
+}
+
+func (c *Cpu) SetInterruptMasterEnable(enabled bool) {
+	c.ime = enabled
+	c.imePending = false
+}
+
+// Interrupts that are both requested and enabled
+func (c *Cpu) pendingInterrupts() byte {
+	return c.interruptFlags & c.interruptEnable & interruptMask
+}
+
+// If IME is set and an interrupt is pending, acknowledge the highest priority
+// one, push PC and jump to its vector. Returns whether an interrupt was taken.
+func (c *Cpu) dispatchInterrupt() (bool, error) {
+	pending := c.pendingInterrupts()
+	if !c.ime || pending == 0 {
+		return false, nil
+	}
+	for interrupt := VBlank; interrupt <= Joypad; interrupt++ {
+		if pending & (1 << interrupt) == 0 {
+			continue
+		}
+		c.ime = false
+		c.interruptFlags &= ^byte(1 << interrupt)
+		// Two wait states before PC is pushed
+		c.internalCycle()
+		c.internalCycle()
+		returnAddress := c.PC.Retrieve()
+		if c.haltBug {
+			// EI; HALT with an interrupt pending returns to the HALT rather
+			// than repeating a byte of the handler
+			c.haltBug = false
+			returnAddress--
+		}
+		if err := c.PushWord(returnAddress); err != nil {
+			return false, err
+		}
+		c.PC.Assign(interrupt.Vector())
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,86 @@
+package cpu
+
+import (
+	"fmt"
+	"types"
+)
+
+type AccessKind int
+
+const (
+	ReadAccess AccessKind = iota
+	WriteAccess
+	// Opcode and operand bytes read at PC
+	ExecuteAccess
+)
+
+func (k AccessKind) String() string {
+	switch k {
+	case ReadAccess:
+		return "read"
+	case WriteAccess:
+		return "write"
+	case ExecuteAccess:
+		return "execute"
+	default:
+		return fmt.Sprintf("Unknown access kind: %d", int(k))
+	}
+}
+
+// A memory access made by the CPU
+type Access struct {
+	Address types.Word
+	// The byte read or written. Reads that fault report 0xFF
+	Value byte
+	Kind AccessKind
+	// Start of the instruction making the access, or the interrupted PC for
+	// interrupt dispatch
+	PC types.Word
+	// Cycles run when the access happened. Only exact within an instruction
+	// in cycle accurate mode, otherwise it's the start of the step
+	Cycle uint64
+}
+
+// Called after every memory access, for watchpoints, heatmaps and the like.
+// Accesses made for debugging (traces, crash dumps) aren't reported
+type Observer func(access Access)
+
+// Returned by AddObserver for removing the observer later
+type ObserverId int
+
+type registeredObserver struct {
+	id ObserverId
+	observer Observer
+}
+
+// Observers are called in the order they were added
+func (c *Cpu) AddObserver(observer Observer) ObserverId {
+	c.nextObserverId++
+	c.observers = append(c.observers, registeredObserver{c.nextObserverId, observer})
+	return c.nextObserverId
+}
+
+func (c *Cpu) RemoveObserver(id ObserverId) {
+	for i, registered := range c.observers {
+		if registered.id == id {
+			c.observers = append(c.observers[:i], c.observers[i + 1:]...)
+			break
+		}
+	}
+	if len(c.observers) == 0 {
+		c.observers = nil
+	}
+}
+
+func (c *Cpu) notifyObservers(address types.Word, value byte, kind AccessKind) {
+	access := Access{
+		Address: address,
+		Value: value,
+		Kind: kind,
+		PC: c.instructionPC,
+		Cycle: c.cycles,
+	}
+	for _, registered := range c.observers {
+		registered.observer(access)
+	}
+}
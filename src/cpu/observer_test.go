@@ -0,0 +1,80 @@
+package cpu
+
+import (
+	"testing"
+	"types"
+)
+
+func TestObservers(t *testing.T) {
+	cpu, mem := setupCpu()
+	cpu.SetCycleAccurate(true)
+	cpu.PC.Assign(types.Word(0x100))
+	cpu.HL.Assign(types.Word(0x300))
+	program := []byte{
+		0x3E, 0x42, // LD A,$42
+		0xEA, 0x00, 0x03, // LD ($0300),A
+		0x7E, // LD A,(HL)
+	}
+	for offset, val := range program {
+		mem.Set(types.Word(0x100 + offset), val)
+	}
+
+	var accesses []Access
+	cpu.AddObserver(func(access Access) {
+		accesses = append(accesses, access)
+	})
+	removed := cpu.AddObserver(func(access Access) {
+		t.Errorf("Removed observer called for %+v", access)
+	})
+	cpu.RemoveObserver(removed)
+	for i := 0; i < 3; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatalf("Error stepping cpu: %v", err)
+		}
+	}
+
+	expected := []Access{
+		{0x100, 0x3E, ExecuteAccess, 0x100, 4},
+		{0x101, 0x42, ExecuteAccess, 0x100, 8},
+		{0x102, 0xEA, ExecuteAccess, 0x102, 12},
+		{0x103, 0x00, ExecuteAccess, 0x102, 16},
+		{0x104, 0x03, ExecuteAccess, 0x102, 20},
+		{0x300, 0x42, WriteAccess, 0x102, 24},
+		{0x105, 0x7E, ExecuteAccess, 0x105, 28},
+		{0x300, 0x42, ReadAccess, 0x105, 32},
+	}
+	if len(accesses) != len(expected) {
+		t.Fatalf("Expected %d accesses, got %d: %+v", len(expected), len(accesses), accesses)
+	}
+	for i, want := range expected {
+		if accesses[i] != want {
+			t.Errorf("Access %d: want %+v, got %+v", i, want, accesses[i])
+		}
+	}
+}
+
+func TestObservers_interruptDispatch(t *testing.T) {
+	cpu, _ := setupCpu()
+	cpu.PC.Assign(types.Word(0x123))
+	cpu.SP.Assign(types.Word(0x200))
+	cpu.SetInterruptMasterEnable(true)
+	cpu.SetInterruptEnable(0x01)
+	cpu.RequestInterrupt(VBlank)
+
+	var accesses []Access
+	cpu.AddObserver(func(access Access) {
+		accesses = append(accesses, access)
+	})
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Error stepping cpu: %v", err)
+	}
+	// Pushing PC shows up as two writes at the interrupted PC
+	if len(accesses) != 2 {
+		t.Fatalf("Expected 2 accesses, got %+v", accesses)
+	}
+	for i, address := range []types.Word{0x1FF, 0x1FE} {
+		if accesses[i].Address != address || accesses[i].Kind != WriteAccess || accesses[i].PC != 0x123 {
+			t.Errorf("Access %d: unexpected %+v", i, accesses[i])
+		}
+	}
+}
@@ -0,0 +1,1043 @@
+// Code generated by gen/main.go from instructions.json. DO NOT EDIT.
+
+package cpu
+
+var MainInstructions = [256]Instruction{
+	0x00: {Mnemonic: "NOP", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x01: {Mnemonic: "LD BC,d16", Length: 3, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0x02: {Mnemonic: "LD (BC),A", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x03: {Mnemonic: "INC BC", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x04: {Mnemonic: "INC B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0H-"},
+	0x05: {Mnemonic: "DEC B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1H-"},
+	0x06: {Mnemonic: "LD B,d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x07: {Mnemonic: "RLCA", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "000C"},
+	0x08: {Mnemonic: "LD (a16),SP", Length: 3, Cycles: 20, CyclesNotTaken: 20, Flags: "----"},
+	0x09: {Mnemonic: "ADD HL,BC", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "-0HC"},
+	0x0A: {Mnemonic: "LD A,(BC)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x0B: {Mnemonic: "DEC BC", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x0C: {Mnemonic: "INC C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0H-"},
+	0x0D: {Mnemonic: "DEC C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1H-"},
+	0x0E: {Mnemonic: "LD C,d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x0F: {Mnemonic: "RRCA", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "000C"},
+	0x10: {Mnemonic: "STOP 0", Length: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x11: {Mnemonic: "LD DE,d16", Length: 3, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0x12: {Mnemonic: "LD (DE),A", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x13: {Mnemonic: "INC DE", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x14: {Mnemonic: "INC D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0H-"},
+	0x15: {Mnemonic: "DEC D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1H-"},
+	0x16: {Mnemonic: "LD D,d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x17: {Mnemonic: "RLA", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "000C"},
+	0x18: {Mnemonic: "JR r8", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0x19: {Mnemonic: "ADD HL,DE", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "-0HC"},
+	0x1A: {Mnemonic: "LD A,(DE)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x1B: {Mnemonic: "DEC DE", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x1C: {Mnemonic: "INC E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0H-"},
+	0x1D: {Mnemonic: "DEC E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1H-"},
+	0x1E: {Mnemonic: "LD E,d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x1F: {Mnemonic: "RRA", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "000C"},
+	0x20: {Mnemonic: "JR NZ,r8", Length: 2, Cycles: 12, CyclesNotTaken: 8, Flags: "----"},
+	0x21: {Mnemonic: "LD HL,d16", Length: 3, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0x22: {Mnemonic: "LD (HL+),A", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x23: {Mnemonic: "INC HL", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x24: {Mnemonic: "INC H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0H-"},
+	0x25: {Mnemonic: "DEC H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1H-"},
+	0x26: {Mnemonic: "LD H,d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x27: {Mnemonic: "DAA", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z-0C"},
+	0x28: {Mnemonic: "JR Z,r8", Length: 2, Cycles: 12, CyclesNotTaken: 8, Flags: "----"},
+	0x29: {Mnemonic: "ADD HL,HL", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "-0HC"},
+	0x2A: {Mnemonic: "LD A,(HL+)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x2B: {Mnemonic: "DEC HL", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x2C: {Mnemonic: "INC L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0H-"},
+	0x2D: {Mnemonic: "DEC L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1H-"},
+	0x2E: {Mnemonic: "LD L,d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x2F: {Mnemonic: "CPL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "-11-"},
+	0x30: {Mnemonic: "JR NC,r8", Length: 2, Cycles: 12, CyclesNotTaken: 8, Flags: "----"},
+	0x31: {Mnemonic: "LD SP,d16", Length: 3, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0x32: {Mnemonic: "LD (HL-),A", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x33: {Mnemonic: "INC SP", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x34: {Mnemonic: "INC (HL)", Length: 1, Cycles: 12, CyclesNotTaken: 12, Flags: "Z0H-"},
+	0x35: {Mnemonic: "DEC (HL)", Length: 1, Cycles: 12, CyclesNotTaken: 12, Flags: "Z1H-"},
+	0x36: {Mnemonic: "LD (HL),d8", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0x37: {Mnemonic: "SCF", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "-001"},
+	0x38: {Mnemonic: "JR C,r8", Length: 2, Cycles: 12, CyclesNotTaken: 8, Flags: "----"},
+	0x39: {Mnemonic: "ADD HL,SP", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "-0HC"},
+	0x3A: {Mnemonic: "LD A,(HL-)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x3B: {Mnemonic: "DEC SP", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x3C: {Mnemonic: "INC A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0H-"},
+	0x3D: {Mnemonic: "DEC A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1H-"},
+	0x3E: {Mnemonic: "LD A,d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x3F: {Mnemonic: "CCF", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "-00C"},
+	0x40: {Mnemonic: "LD B,B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x41: {Mnemonic: "LD B,C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x42: {Mnemonic: "LD B,D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x43: {Mnemonic: "LD B,E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x44: {Mnemonic: "LD B,H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x45: {Mnemonic: "LD B,L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x46: {Mnemonic: "LD B,(HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x47: {Mnemonic: "LD B,A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x48: {Mnemonic: "LD C,B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x49: {Mnemonic: "LD C,C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x4A: {Mnemonic: "LD C,D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x4B: {Mnemonic: "LD C,E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x4C: {Mnemonic: "LD C,H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x4D: {Mnemonic: "LD C,L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x4E: {Mnemonic: "LD C,(HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x4F: {Mnemonic: "LD C,A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x50: {Mnemonic: "LD D,B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x51: {Mnemonic: "LD D,C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x52: {Mnemonic: "LD D,D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x53: {Mnemonic: "LD D,E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x54: {Mnemonic: "LD D,H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x55: {Mnemonic: "LD D,L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x56: {Mnemonic: "LD D,(HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x57: {Mnemonic: "LD D,A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x58: {Mnemonic: "LD E,B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x59: {Mnemonic: "LD E,C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x5A: {Mnemonic: "LD E,D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x5B: {Mnemonic: "LD E,E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x5C: {Mnemonic: "LD E,H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x5D: {Mnemonic: "LD E,L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x5E: {Mnemonic: "LD E,(HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x5F: {Mnemonic: "LD E,A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x60: {Mnemonic: "LD H,B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x61: {Mnemonic: "LD H,C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x62: {Mnemonic: "LD H,D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x63: {Mnemonic: "LD H,E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x64: {Mnemonic: "LD H,H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x65: {Mnemonic: "LD H,L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x66: {Mnemonic: "LD H,(HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x67: {Mnemonic: "LD H,A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x68: {Mnemonic: "LD L,B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x69: {Mnemonic: "LD L,C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x6A: {Mnemonic: "LD L,D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x6B: {Mnemonic: "LD L,E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x6C: {Mnemonic: "LD L,H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x6D: {Mnemonic: "LD L,L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x6E: {Mnemonic: "LD L,(HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x6F: {Mnemonic: "LD L,A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x70: {Mnemonic: "LD (HL),B", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x71: {Mnemonic: "LD (HL),C", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x72: {Mnemonic: "LD (HL),D", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x73: {Mnemonic: "LD (HL),E", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x74: {Mnemonic: "LD (HL),H", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x75: {Mnemonic: "LD (HL),L", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x76: {Mnemonic: "HALT", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x77: {Mnemonic: "LD (HL),A", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x78: {Mnemonic: "LD A,B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x79: {Mnemonic: "LD A,C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x7A: {Mnemonic: "LD A,D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x7B: {Mnemonic: "LD A,E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x7C: {Mnemonic: "LD A,H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x7D: {Mnemonic: "LD A,L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x7E: {Mnemonic: "LD A,(HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x7F: {Mnemonic: "LD A,A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0x80: {Mnemonic: "ADD A,B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x81: {Mnemonic: "ADD A,C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x82: {Mnemonic: "ADD A,D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x83: {Mnemonic: "ADD A,E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x84: {Mnemonic: "ADD A,H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x85: {Mnemonic: "ADD A,L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x86: {Mnemonic: "ADD A,(HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "Z0HC"},
+	0x87: {Mnemonic: "ADD A,A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x88: {Mnemonic: "ADC A,B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x89: {Mnemonic: "ADC A,C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x8A: {Mnemonic: "ADC A,D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x8B: {Mnemonic: "ADC A,E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x8C: {Mnemonic: "ADC A,H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x8D: {Mnemonic: "ADC A,L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x8E: {Mnemonic: "ADC A,(HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "Z0HC"},
+	0x8F: {Mnemonic: "ADC A,A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z0HC"},
+	0x90: {Mnemonic: "SUB B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x91: {Mnemonic: "SUB C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x92: {Mnemonic: "SUB D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x93: {Mnemonic: "SUB E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x94: {Mnemonic: "SUB H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x95: {Mnemonic: "SUB L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x96: {Mnemonic: "SUB (HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "Z1HC"},
+	0x97: {Mnemonic: "SUB A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x98: {Mnemonic: "SBC A,B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x99: {Mnemonic: "SBC A,C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x9A: {Mnemonic: "SBC A,D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x9B: {Mnemonic: "SBC A,E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x9C: {Mnemonic: "SBC A,H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x9D: {Mnemonic: "SBC A,L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0x9E: {Mnemonic: "SBC A,(HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "Z1HC"},
+	0x9F: {Mnemonic: "SBC A,A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0xA0: {Mnemonic: "AND B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z010"},
+	0xA1: {Mnemonic: "AND C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z010"},
+	0xA2: {Mnemonic: "AND D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z010"},
+	0xA3: {Mnemonic: "AND E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z010"},
+	0xA4: {Mnemonic: "AND H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z010"},
+	0xA5: {Mnemonic: "AND L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z010"},
+	0xA6: {Mnemonic: "AND (HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "Z010"},
+	0xA7: {Mnemonic: "AND A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z010"},
+	0xA8: {Mnemonic: "XOR B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xA9: {Mnemonic: "XOR C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xAA: {Mnemonic: "XOR D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xAB: {Mnemonic: "XOR E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xAC: {Mnemonic: "XOR H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xAD: {Mnemonic: "XOR L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xAE: {Mnemonic: "XOR (HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0xAF: {Mnemonic: "XOR A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xB0: {Mnemonic: "OR B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xB1: {Mnemonic: "OR C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xB2: {Mnemonic: "OR D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xB3: {Mnemonic: "OR E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xB4: {Mnemonic: "OR H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xB5: {Mnemonic: "OR L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xB6: {Mnemonic: "OR (HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0xB7: {Mnemonic: "OR A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000"},
+	0xB8: {Mnemonic: "CP B", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0xB9: {Mnemonic: "CP C", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0xBA: {Mnemonic: "CP D", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0xBB: {Mnemonic: "CP E", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0xBC: {Mnemonic: "CP H", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0xBD: {Mnemonic: "CP L", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0xBE: {Mnemonic: "CP (HL)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "Z1HC"},
+	0xBF: {Mnemonic: "CP A", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "Z1HC"},
+	0xC0: {Mnemonic: "RET NZ", Length: 1, Cycles: 20, CyclesNotTaken: 8, Flags: "----"},
+	0xC1: {Mnemonic: "POP BC", Length: 1, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0xC2: {Mnemonic: "JP NZ,a16", Length: 3, Cycles: 16, CyclesNotTaken: 12, Flags: "----"},
+	0xC3: {Mnemonic: "JP a16", Length: 3, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xC4: {Mnemonic: "CALL NZ,a16", Length: 3, Cycles: 24, CyclesNotTaken: 12, Flags: "----"},
+	0xC5: {Mnemonic: "PUSH BC", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xC6: {Mnemonic: "ADD A,d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z0HC"},
+	0xC7: {Mnemonic: "RST 00H", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xC8: {Mnemonic: "RET Z", Length: 1, Cycles: 20, CyclesNotTaken: 8, Flags: "----"},
+	0xC9: {Mnemonic: "RET", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xCA: {Mnemonic: "JP Z,a16", Length: 3, Cycles: 16, CyclesNotTaken: 12, Flags: "----"},
+	0xCB: {Mnemonic: "PREFIX CB", Length: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xCC: {Mnemonic: "CALL Z,a16", Length: 3, Cycles: 24, CyclesNotTaken: 12, Flags: "----"},
+	0xCD: {Mnemonic: "CALL a16", Length: 3, Cycles: 24, CyclesNotTaken: 24, Flags: "----"},
+	0xCE: {Mnemonic: "ADC A,d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z0HC"},
+	0xCF: {Mnemonic: "RST 08H", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xD0: {Mnemonic: "RET NC", Length: 1, Cycles: 20, CyclesNotTaken: 8, Flags: "----"},
+	0xD1: {Mnemonic: "POP DE", Length: 1, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0xD2: {Mnemonic: "JP NC,a16", Length: 3, Cycles: 16, CyclesNotTaken: 12, Flags: "----"},
+	0xD3: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xD4: {Mnemonic: "CALL NC,a16", Length: 3, Cycles: 24, CyclesNotTaken: 12, Flags: "----"},
+	0xD5: {Mnemonic: "PUSH DE", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xD6: {Mnemonic: "SUB d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z1HC"},
+	0xD7: {Mnemonic: "RST 10H", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xD8: {Mnemonic: "RET C", Length: 1, Cycles: 20, CyclesNotTaken: 8, Flags: "----"},
+	0xD9: {Mnemonic: "RETI", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xDA: {Mnemonic: "JP C,a16", Length: 3, Cycles: 16, CyclesNotTaken: 12, Flags: "----"},
+	0xDB: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xDC: {Mnemonic: "CALL C,a16", Length: 3, Cycles: 24, CyclesNotTaken: 12, Flags: "----"},
+	0xDD: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xDE: {Mnemonic: "SBC A,d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z1HC"},
+	0xDF: {Mnemonic: "RST 18H", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xE0: {Mnemonic: "LDH (a8),A", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0xE1: {Mnemonic: "POP HL", Length: 1, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0xE2: {Mnemonic: "LD (C),A", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xE3: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xE4: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xE5: {Mnemonic: "PUSH HL", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xE6: {Mnemonic: "AND d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z010"},
+	0xE7: {Mnemonic: "RST 20H", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xE8: {Mnemonic: "ADD SP,r8", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "00HC"},
+	0xE9: {Mnemonic: "JP (HL)", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xEA: {Mnemonic: "LD (a16),A", Length: 3, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xEB: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xEC: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xED: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xEE: {Mnemonic: "XOR d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0xEF: {Mnemonic: "RST 28H", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xF0: {Mnemonic: "LDH A,(a8)", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "----"},
+	0xF1: {Mnemonic: "POP AF", Length: 1, Cycles: 12, CyclesNotTaken: 12, Flags: "ZNHC"},
+	0xF2: {Mnemonic: "LD A,(C)", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xF3: {Mnemonic: "DI", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xF4: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xF5: {Mnemonic: "PUSH AF", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xF6: {Mnemonic: "OR d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0xF7: {Mnemonic: "RST 30H", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xF8: {Mnemonic: "LD HL,SP+r8", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "00HC"},
+	0xF9: {Mnemonic: "LD SP,HL", Length: 1, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xFA: {Mnemonic: "LD A,(a16)", Length: 3, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xFB: {Mnemonic: "EI", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xFC: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xFD: {Mnemonic: "ILLEGAL", Length: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xFE: {Mnemonic: "CP d8", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z1HC"},
+	0xFF: {Mnemonic: "RST 38H", Length: 1, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+}
+
+var CBInstructions = [256]Instruction{
+	0x00: {Mnemonic: "RLC B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x01: {Mnemonic: "RLC C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x02: {Mnemonic: "RLC D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x03: {Mnemonic: "RLC E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x04: {Mnemonic: "RLC H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x05: {Mnemonic: "RLC L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x06: {Mnemonic: "RLC (HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "Z00C"},
+	0x07: {Mnemonic: "RLC A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x08: {Mnemonic: "RRC B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x09: {Mnemonic: "RRC C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x0A: {Mnemonic: "RRC D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x0B: {Mnemonic: "RRC E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x0C: {Mnemonic: "RRC H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x0D: {Mnemonic: "RRC L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x0E: {Mnemonic: "RRC (HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "Z00C"},
+	0x0F: {Mnemonic: "RRC A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x10: {Mnemonic: "RL B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x11: {Mnemonic: "RL C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x12: {Mnemonic: "RL D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x13: {Mnemonic: "RL E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x14: {Mnemonic: "RL H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x15: {Mnemonic: "RL L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x16: {Mnemonic: "RL (HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "Z00C"},
+	0x17: {Mnemonic: "RL A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x18: {Mnemonic: "RR B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x19: {Mnemonic: "RR C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x1A: {Mnemonic: "RR D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x1B: {Mnemonic: "RR E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x1C: {Mnemonic: "RR H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x1D: {Mnemonic: "RR L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x1E: {Mnemonic: "RR (HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "Z00C"},
+	0x1F: {Mnemonic: "RR A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x20: {Mnemonic: "SLA B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x21: {Mnemonic: "SLA C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x22: {Mnemonic: "SLA D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x23: {Mnemonic: "SLA E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x24: {Mnemonic: "SLA H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x25: {Mnemonic: "SLA L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x26: {Mnemonic: "SLA (HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "Z00C"},
+	0x27: {Mnemonic: "SLA A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x28: {Mnemonic: "SRA B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x29: {Mnemonic: "SRA C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x2A: {Mnemonic: "SRA D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x2B: {Mnemonic: "SRA E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x2C: {Mnemonic: "SRA H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x2D: {Mnemonic: "SRA L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x2E: {Mnemonic: "SRA (HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "Z00C"},
+	0x2F: {Mnemonic: "SRA A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x30: {Mnemonic: "SWAP B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0x31: {Mnemonic: "SWAP C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0x32: {Mnemonic: "SWAP D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0x33: {Mnemonic: "SWAP E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0x34: {Mnemonic: "SWAP H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0x35: {Mnemonic: "SWAP L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0x36: {Mnemonic: "SWAP (HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "Z000"},
+	0x37: {Mnemonic: "SWAP A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z000"},
+	0x38: {Mnemonic: "SRL B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x39: {Mnemonic: "SRL C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x3A: {Mnemonic: "SRL D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x3B: {Mnemonic: "SRL E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x3C: {Mnemonic: "SRL H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x3D: {Mnemonic: "SRL L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x3E: {Mnemonic: "SRL (HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "Z00C"},
+	0x3F: {Mnemonic: "SRL A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z00C"},
+	0x40: {Mnemonic: "BIT 0,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x41: {Mnemonic: "BIT 0,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x42: {Mnemonic: "BIT 0,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x43: {Mnemonic: "BIT 0,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x44: {Mnemonic: "BIT 0,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x45: {Mnemonic: "BIT 0,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x46: {Mnemonic: "BIT 0,(HL)", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "Z01-"},
+	0x47: {Mnemonic: "BIT 0,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x48: {Mnemonic: "BIT 1,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x49: {Mnemonic: "BIT 1,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x4A: {Mnemonic: "BIT 1,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x4B: {Mnemonic: "BIT 1,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x4C: {Mnemonic: "BIT 1,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x4D: {Mnemonic: "BIT 1,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x4E: {Mnemonic: "BIT 1,(HL)", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "Z01-"},
+	0x4F: {Mnemonic: "BIT 1,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x50: {Mnemonic: "BIT 2,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x51: {Mnemonic: "BIT 2,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x52: {Mnemonic: "BIT 2,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x53: {Mnemonic: "BIT 2,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x54: {Mnemonic: "BIT 2,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x55: {Mnemonic: "BIT 2,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x56: {Mnemonic: "BIT 2,(HL)", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "Z01-"},
+	0x57: {Mnemonic: "BIT 2,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x58: {Mnemonic: "BIT 3,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x59: {Mnemonic: "BIT 3,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x5A: {Mnemonic: "BIT 3,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x5B: {Mnemonic: "BIT 3,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x5C: {Mnemonic: "BIT 3,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x5D: {Mnemonic: "BIT 3,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x5E: {Mnemonic: "BIT 3,(HL)", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "Z01-"},
+	0x5F: {Mnemonic: "BIT 3,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x60: {Mnemonic: "BIT 4,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x61: {Mnemonic: "BIT 4,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x62: {Mnemonic: "BIT 4,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x63: {Mnemonic: "BIT 4,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x64: {Mnemonic: "BIT 4,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x65: {Mnemonic: "BIT 4,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x66: {Mnemonic: "BIT 4,(HL)", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "Z01-"},
+	0x67: {Mnemonic: "BIT 4,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x68: {Mnemonic: "BIT 5,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x69: {Mnemonic: "BIT 5,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x6A: {Mnemonic: "BIT 5,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x6B: {Mnemonic: "BIT 5,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x6C: {Mnemonic: "BIT 5,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x6D: {Mnemonic: "BIT 5,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x6E: {Mnemonic: "BIT 5,(HL)", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "Z01-"},
+	0x6F: {Mnemonic: "BIT 5,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x70: {Mnemonic: "BIT 6,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x71: {Mnemonic: "BIT 6,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x72: {Mnemonic: "BIT 6,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x73: {Mnemonic: "BIT 6,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x74: {Mnemonic: "BIT 6,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x75: {Mnemonic: "BIT 6,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x76: {Mnemonic: "BIT 6,(HL)", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "Z01-"},
+	0x77: {Mnemonic: "BIT 6,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x78: {Mnemonic: "BIT 7,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x79: {Mnemonic: "BIT 7,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x7A: {Mnemonic: "BIT 7,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x7B: {Mnemonic: "BIT 7,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x7C: {Mnemonic: "BIT 7,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x7D: {Mnemonic: "BIT 7,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x7E: {Mnemonic: "BIT 7,(HL)", Length: 2, Cycles: 12, CyclesNotTaken: 12, Flags: "Z01-"},
+	0x7F: {Mnemonic: "BIT 7,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "Z01-"},
+	0x80: {Mnemonic: "RES 0,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x81: {Mnemonic: "RES 0,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x82: {Mnemonic: "RES 0,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x83: {Mnemonic: "RES 0,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x84: {Mnemonic: "RES 0,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x85: {Mnemonic: "RES 0,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x86: {Mnemonic: "RES 0,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0x87: {Mnemonic: "RES 0,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x88: {Mnemonic: "RES 1,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x89: {Mnemonic: "RES 1,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x8A: {Mnemonic: "RES 1,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x8B: {Mnemonic: "RES 1,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x8C: {Mnemonic: "RES 1,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x8D: {Mnemonic: "RES 1,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x8E: {Mnemonic: "RES 1,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0x8F: {Mnemonic: "RES 1,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x90: {Mnemonic: "RES 2,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x91: {Mnemonic: "RES 2,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x92: {Mnemonic: "RES 2,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x93: {Mnemonic: "RES 2,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x94: {Mnemonic: "RES 2,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x95: {Mnemonic: "RES 2,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x96: {Mnemonic: "RES 2,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0x97: {Mnemonic: "RES 2,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x98: {Mnemonic: "RES 3,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x99: {Mnemonic: "RES 3,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x9A: {Mnemonic: "RES 3,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x9B: {Mnemonic: "RES 3,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x9C: {Mnemonic: "RES 3,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x9D: {Mnemonic: "RES 3,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0x9E: {Mnemonic: "RES 3,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0x9F: {Mnemonic: "RES 3,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xA0: {Mnemonic: "RES 4,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xA1: {Mnemonic: "RES 4,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xA2: {Mnemonic: "RES 4,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xA3: {Mnemonic: "RES 4,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xA4: {Mnemonic: "RES 4,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xA5: {Mnemonic: "RES 4,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xA6: {Mnemonic: "RES 4,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xA7: {Mnemonic: "RES 4,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xA8: {Mnemonic: "RES 5,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xA9: {Mnemonic: "RES 5,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xAA: {Mnemonic: "RES 5,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xAB: {Mnemonic: "RES 5,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xAC: {Mnemonic: "RES 5,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xAD: {Mnemonic: "RES 5,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xAE: {Mnemonic: "RES 5,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xAF: {Mnemonic: "RES 5,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xB0: {Mnemonic: "RES 6,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xB1: {Mnemonic: "RES 6,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xB2: {Mnemonic: "RES 6,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xB3: {Mnemonic: "RES 6,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xB4: {Mnemonic: "RES 6,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xB5: {Mnemonic: "RES 6,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xB6: {Mnemonic: "RES 6,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xB7: {Mnemonic: "RES 6,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xB8: {Mnemonic: "RES 7,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xB9: {Mnemonic: "RES 7,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xBA: {Mnemonic: "RES 7,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xBB: {Mnemonic: "RES 7,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xBC: {Mnemonic: "RES 7,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xBD: {Mnemonic: "RES 7,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xBE: {Mnemonic: "RES 7,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xBF: {Mnemonic: "RES 7,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xC0: {Mnemonic: "SET 0,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xC1: {Mnemonic: "SET 0,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xC2: {Mnemonic: "SET 0,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xC3: {Mnemonic: "SET 0,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xC4: {Mnemonic: "SET 0,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xC5: {Mnemonic: "SET 0,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xC6: {Mnemonic: "SET 0,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xC7: {Mnemonic: "SET 0,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xC8: {Mnemonic: "SET 1,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xC9: {Mnemonic: "SET 1,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xCA: {Mnemonic: "SET 1,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xCB: {Mnemonic: "SET 1,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xCC: {Mnemonic: "SET 1,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xCD: {Mnemonic: "SET 1,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xCE: {Mnemonic: "SET 1,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xCF: {Mnemonic: "SET 1,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xD0: {Mnemonic: "SET 2,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xD1: {Mnemonic: "SET 2,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xD2: {Mnemonic: "SET 2,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xD3: {Mnemonic: "SET 2,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xD4: {Mnemonic: "SET 2,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xD5: {Mnemonic: "SET 2,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xD6: {Mnemonic: "SET 2,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xD7: {Mnemonic: "SET 2,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xD8: {Mnemonic: "SET 3,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xD9: {Mnemonic: "SET 3,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xDA: {Mnemonic: "SET 3,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xDB: {Mnemonic: "SET 3,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xDC: {Mnemonic: "SET 3,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xDD: {Mnemonic: "SET 3,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xDE: {Mnemonic: "SET 3,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xDF: {Mnemonic: "SET 3,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xE0: {Mnemonic: "SET 4,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xE1: {Mnemonic: "SET 4,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xE2: {Mnemonic: "SET 4,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xE3: {Mnemonic: "SET 4,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xE4: {Mnemonic: "SET 4,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xE5: {Mnemonic: "SET 4,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xE6: {Mnemonic: "SET 4,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xE7: {Mnemonic: "SET 4,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xE8: {Mnemonic: "SET 5,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xE9: {Mnemonic: "SET 5,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xEA: {Mnemonic: "SET 5,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xEB: {Mnemonic: "SET 5,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xEC: {Mnemonic: "SET 5,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xED: {Mnemonic: "SET 5,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xEE: {Mnemonic: "SET 5,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xEF: {Mnemonic: "SET 5,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xF0: {Mnemonic: "SET 6,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xF1: {Mnemonic: "SET 6,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xF2: {Mnemonic: "SET 6,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xF3: {Mnemonic: "SET 6,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xF4: {Mnemonic: "SET 6,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xF5: {Mnemonic: "SET 6,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xF6: {Mnemonic: "SET 6,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xF7: {Mnemonic: "SET 6,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xF8: {Mnemonic: "SET 7,B", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xF9: {Mnemonic: "SET 7,C", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xFA: {Mnemonic: "SET 7,D", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xFB: {Mnemonic: "SET 7,E", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xFC: {Mnemonic: "SET 7,H", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xFD: {Mnemonic: "SET 7,L", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+	0xFE: {Mnemonic: "SET 7,(HL)", Length: 2, Cycles: 16, CyclesNotTaken: 16, Flags: "----"},
+	0xFF: {Mnemonic: "SET 7,A", Length: 2, Cycles: 8, CyclesNotTaken: 8, Flags: "----"},
+}
+
+func (c *Cpu) generateOpCodes() map[byte]OpCode {
+	codes := make(map[byte]OpCode)
+	codes[0x00] = &NoOpCode{BaseOpCode: BaseOpCode{code: 0x00, length: 1}}                                                                       // NOP
+	codes[0x01] = &Ld16BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x01, length: 3}, r1: &c.BC}                                              // LD BC,d16
+	codes[0x02] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x02, length: 1}, r1: &c.BC, r2: &c.A}                                        // LD (BC),A
+	codes[0x03] = &IncDec16Bit{BaseOpCode: BaseOpCode{code: 0x03, length: 1}, target: &c.BC, mod: Increment}                                     // INC BC
+	codes[0x04] = &Inc8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x04, length: 1}, r1: &c.B}                                                     // INC B
+	codes[0x05] = &Dec8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x05, length: 1}, r1: &c.B}                                                     // DEC B
+	codes[0x06] = &Ld8BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x06, length: 2}, r1: &c.B}                                                // LD B,d8
+	codes[0x07] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x07, length: 1}, r1: &c.A, direction: Left, circular: true, isCB: false}           // RLCA
+	codes[0x08] = &LdSPIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x08, length: 3}, r1: &c.SP}                                                   // LD (a16),SP
+	codes[0x09] = &Add16BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x09, length: 1}, r1: &c.HL, r2: &c.BC}                                        // ADD HL,BC
+	codes[0x0A] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x0A, length: 1}, r1: &c.A, r2: &c.BC}                                        // LD A,(BC)
+	codes[0x0B] = &IncDec16Bit{BaseOpCode: BaseOpCode{code: 0x0B, length: 1}, target: &c.BC, mod: Decrement}                                     // DEC BC
+	codes[0x0C] = &Inc8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x0C, length: 1}, r1: &c.C}                                                     // INC C
+	codes[0x0D] = &Dec8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x0D, length: 1}, r1: &c.C}                                                     // DEC C
+	codes[0x0E] = &Ld8BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x0E, length: 2}, r1: &c.C}                                                // LD C,d8
+	codes[0x0F] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x0F, length: 1}, r1: &c.A, direction: Right, circular: true, isCB: false}          // RRCA
+	codes[0x10] = &StopOpCode{BaseOpCode: BaseOpCode{code: 0x10, length: 2}}                                                                     // STOP 0
+	codes[0x11] = &Ld16BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x11, length: 3}, r1: &c.DE}                                              // LD DE,d16
+	codes[0x12] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x12, length: 1}, r1: &c.DE, r2: &c.A}                                        // LD (DE),A
+	codes[0x13] = &IncDec16Bit{BaseOpCode: BaseOpCode{code: 0x13, length: 1}, target: &c.DE, mod: Increment}                                     // INC DE
+	codes[0x14] = &Inc8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x14, length: 1}, r1: &c.D}                                                     // INC D
+	codes[0x15] = &Dec8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x15, length: 1}, r1: &c.D}                                                     // DEC D
+	codes[0x16] = &Ld8BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x16, length: 2}, r1: &c.D}                                                // LD D,d8
+	codes[0x17] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x17, length: 1}, r1: &c.A, direction: Left, circular: false, isCB: false}          // RLA
+	codes[0x18] = &JumpRelativeOpCode{BaseOpCode: BaseOpCode{code: 0x18, length: 2}, condition: Always}                                          // JR r8
+	codes[0x19] = &Add16BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x19, length: 1}, r1: &c.HL, r2: &c.DE}                                        // ADD HL,DE
+	codes[0x1A] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x1A, length: 1}, r1: &c.A, r2: &c.DE}                                        // LD A,(DE)
+	codes[0x1B] = &IncDec16Bit{BaseOpCode: BaseOpCode{code: 0x1B, length: 1}, target: &c.DE, mod: Decrement}                                     // DEC DE
+	codes[0x1C] = &Inc8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x1C, length: 1}, r1: &c.E}                                                     // INC E
+	codes[0x1D] = &Dec8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x1D, length: 1}, r1: &c.E}                                                     // DEC E
+	codes[0x1E] = &Ld8BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x1E, length: 2}, r1: &c.E}                                                // LD E,d8
+	codes[0x1F] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x1F, length: 1}, r1: &c.A, direction: Right, circular: false, isCB: false}         // RRA
+	codes[0x20] = &JumpRelativeOpCode{BaseOpCode: BaseOpCode{code: 0x20, length: 2}, condition: NotZero}                                         // JR NZ,r8
+	codes[0x21] = &Ld16BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x21, length: 3}, r1: &c.HL}                                              // LD HL,d16
+	codes[0x22] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x22, length: 1}, r1: &c.HL, r2: &c.A, incrementR1: true}                     // LD (HL+),A
+	codes[0x23] = &IncDec16Bit{BaseOpCode: BaseOpCode{code: 0x23, length: 1}, target: &c.HL, mod: Increment}                                     // INC HL
+	codes[0x24] = &Inc8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x24, length: 1}, r1: &c.H}                                                     // INC H
+	codes[0x25] = &Dec8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x25, length: 1}, r1: &c.H}                                                     // DEC H
+	codes[0x26] = &Ld8BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x26, length: 2}, r1: &c.H}                                                // LD H,d8
+	codes[0x27] = &DecimalAdjustOpCode{BaseOpCode: BaseOpCode{code: 0x27, length: 1}, r1: &c.A}                                                  // DAA
+	codes[0x28] = &JumpRelativeOpCode{BaseOpCode: BaseOpCode{code: 0x28, length: 2}, condition: Zero}                                            // JR Z,r8
+	codes[0x29] = &Add16BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x29, length: 1}, r1: &c.HL, r2: &c.HL}                                        // ADD HL,HL
+	codes[0x2A] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x2A, length: 1}, r1: &c.A, r2: &c.HL, incrementR2: true}                     // LD A,(HL+)
+	codes[0x2B] = &IncDec16Bit{BaseOpCode: BaseOpCode{code: 0x2B, length: 1}, target: &c.HL, mod: Decrement}                                     // DEC HL
+	codes[0x2C] = &Inc8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x2C, length: 1}, r1: &c.L}                                                     // INC L
+	codes[0x2D] = &Dec8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x2D, length: 1}, r1: &c.L}                                                     // DEC L
+	codes[0x2E] = &Ld8BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x2E, length: 2}, r1: &c.L}                                                // LD L,d8
+	codes[0x2F] = &ComplementOpCode{BaseOpCode: BaseOpCode{code: 0x2F, length: 1}, r1: &c.A}                                                     // CPL
+	codes[0x30] = &JumpRelativeOpCode{BaseOpCode: BaseOpCode{code: 0x30, length: 2}, condition: NoCarry}                                         // JR NC,r8
+	codes[0x31] = &Ld16BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x31, length: 3}, r1: &c.SP}                                              // LD SP,d16
+	codes[0x32] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x32, length: 1}, r1: &c.HL, r2: &c.A, decrementR1: true}                     // LD (HL-),A
+	codes[0x33] = &IncDec16Bit{BaseOpCode: BaseOpCode{code: 0x33, length: 1}, target: &c.SP, mod: Increment}                                     // INC SP
+	codes[0x34] = &Inc8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x34, length: 1}, r1: &c.HL}                                                    // INC (HL)
+	codes[0x35] = &Dec8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x35, length: 1}, r1: &c.HL}                                                    // DEC (HL)
+	codes[0x36] = &LdMemoryImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x36, length: 2}, r1: &c.HL}                                             // LD (HL),d8
+	codes[0x37] = &CarryFlagOpCode{BaseOpCode: BaseOpCode{code: 0x37, length: 1}, complement: false}                                             // SCF
+	codes[0x38] = &JumpRelativeOpCode{BaseOpCode: BaseOpCode{code: 0x38, length: 2}, condition: Carry}                                           // JR C,r8
+	codes[0x39] = &Add16BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x39, length: 1}, r1: &c.HL, r2: &c.SP}                                        // ADD HL,SP
+	codes[0x3A] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x3A, length: 1}, r1: &c.A, r2: &c.HL, decrementR2: true}                     // LD A,(HL-)
+	codes[0x3B] = &IncDec16Bit{BaseOpCode: BaseOpCode{code: 0x3B, length: 1}, target: &c.SP, mod: Decrement}                                     // DEC SP
+	codes[0x3C] = &Inc8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x3C, length: 1}, r1: &c.A}                                                     // INC A
+	codes[0x3D] = &Dec8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x3D, length: 1}, r1: &c.A}                                                     // DEC A
+	codes[0x3E] = &Ld8BitImmediateOpCode{BaseOpCode: BaseOpCode{code: 0x3E, length: 2}, r1: &c.A}                                                // LD A,d8
+	codes[0x3F] = &CarryFlagOpCode{BaseOpCode: BaseOpCode{code: 0x3F, length: 1}, complement: true}                                              // CCF
+	codes[0x40] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x40, length: 1}, r1: &c.B, r2: &c.B}                                       // LD B,B
+	codes[0x41] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x41, length: 1}, r1: &c.B, r2: &c.C}                                       // LD B,C
+	codes[0x42] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x42, length: 1}, r1: &c.B, r2: &c.D}                                       // LD B,D
+	codes[0x43] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x43, length: 1}, r1: &c.B, r2: &c.E}                                       // LD B,E
+	codes[0x44] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x44, length: 1}, r1: &c.B, r2: &c.H}                                       // LD B,H
+	codes[0x45] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x45, length: 1}, r1: &c.B, r2: &c.L}                                       // LD B,L
+	codes[0x46] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x46, length: 1}, r1: &c.B, r2: &c.HL}                                        // LD B,(HL)
+	codes[0x47] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x47, length: 1}, r1: &c.B, r2: &c.A}                                       // LD B,A
+	codes[0x48] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x48, length: 1}, r1: &c.C, r2: &c.B}                                       // LD C,B
+	codes[0x49] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x49, length: 1}, r1: &c.C, r2: &c.C}                                       // LD C,C
+	codes[0x4A] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x4A, length: 1}, r1: &c.C, r2: &c.D}                                       // LD C,D
+	codes[0x4B] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x4B, length: 1}, r1: &c.C, r2: &c.E}                                       // LD C,E
+	codes[0x4C] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x4C, length: 1}, r1: &c.C, r2: &c.H}                                       // LD C,H
+	codes[0x4D] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x4D, length: 1}, r1: &c.C, r2: &c.L}                                       // LD C,L
+	codes[0x4E] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x4E, length: 1}, r1: &c.C, r2: &c.HL}                                        // LD C,(HL)
+	codes[0x4F] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x4F, length: 1}, r1: &c.C, r2: &c.A}                                       // LD C,A
+	codes[0x50] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x50, length: 1}, r1: &c.D, r2: &c.B}                                       // LD D,B
+	codes[0x51] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x51, length: 1}, r1: &c.D, r2: &c.C}                                       // LD D,C
+	codes[0x52] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x52, length: 1}, r1: &c.D, r2: &c.D}                                       // LD D,D
+	codes[0x53] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x53, length: 1}, r1: &c.D, r2: &c.E}                                       // LD D,E
+	codes[0x54] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x54, length: 1}, r1: &c.D, r2: &c.H}                                       // LD D,H
+	codes[0x55] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x55, length: 1}, r1: &c.D, r2: &c.L}                                       // LD D,L
+	codes[0x56] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x56, length: 1}, r1: &c.D, r2: &c.HL}                                        // LD D,(HL)
+	codes[0x57] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x57, length: 1}, r1: &c.D, r2: &c.A}                                       // LD D,A
+	codes[0x58] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x58, length: 1}, r1: &c.E, r2: &c.B}                                       // LD E,B
+	codes[0x59] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x59, length: 1}, r1: &c.E, r2: &c.C}                                       // LD E,C
+	codes[0x5A] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x5A, length: 1}, r1: &c.E, r2: &c.D}                                       // LD E,D
+	codes[0x5B] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x5B, length: 1}, r1: &c.E, r2: &c.E}                                       // LD E,E
+	codes[0x5C] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x5C, length: 1}, r1: &c.E, r2: &c.H}                                       // LD E,H
+	codes[0x5D] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x5D, length: 1}, r1: &c.E, r2: &c.L}                                       // LD E,L
+	codes[0x5E] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x5E, length: 1}, r1: &c.E, r2: &c.HL}                                        // LD E,(HL)
+	codes[0x5F] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x5F, length: 1}, r1: &c.E, r2: &c.A}                                       // LD E,A
+	codes[0x60] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x60, length: 1}, r1: &c.H, r2: &c.B}                                       // LD H,B
+	codes[0x61] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x61, length: 1}, r1: &c.H, r2: &c.C}                                       // LD H,C
+	codes[0x62] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x62, length: 1}, r1: &c.H, r2: &c.D}                                       // LD H,D
+	codes[0x63] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x63, length: 1}, r1: &c.H, r2: &c.E}                                       // LD H,E
+	codes[0x64] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x64, length: 1}, r1: &c.H, r2: &c.H}                                       // LD H,H
+	codes[0x65] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x65, length: 1}, r1: &c.H, r2: &c.L}                                       // LD H,L
+	codes[0x66] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x66, length: 1}, r1: &c.H, r2: &c.HL}                                        // LD H,(HL)
+	codes[0x67] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x67, length: 1}, r1: &c.H, r2: &c.A}                                       // LD H,A
+	codes[0x68] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x68, length: 1}, r1: &c.L, r2: &c.B}                                       // LD L,B
+	codes[0x69] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x69, length: 1}, r1: &c.L, r2: &c.C}                                       // LD L,C
+	codes[0x6A] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x6A, length: 1}, r1: &c.L, r2: &c.D}                                       // LD L,D
+	codes[0x6B] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x6B, length: 1}, r1: &c.L, r2: &c.E}                                       // LD L,E
+	codes[0x6C] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x6C, length: 1}, r1: &c.L, r2: &c.H}                                       // LD L,H
+	codes[0x6D] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x6D, length: 1}, r1: &c.L, r2: &c.L}                                       // LD L,L
+	codes[0x6E] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x6E, length: 1}, r1: &c.L, r2: &c.HL}                                        // LD L,(HL)
+	codes[0x6F] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x6F, length: 1}, r1: &c.L, r2: &c.A}                                       // LD L,A
+	codes[0x70] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x70, length: 1}, r1: &c.HL, r2: &c.B}                                        // LD (HL),B
+	codes[0x71] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x71, length: 1}, r1: &c.HL, r2: &c.C}                                        // LD (HL),C
+	codes[0x72] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x72, length: 1}, r1: &c.HL, r2: &c.D}                                        // LD (HL),D
+	codes[0x73] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x73, length: 1}, r1: &c.HL, r2: &c.E}                                        // LD (HL),E
+	codes[0x74] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x74, length: 1}, r1: &c.HL, r2: &c.H}                                        // LD (HL),H
+	codes[0x75] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x75, length: 1}, r1: &c.HL, r2: &c.L}                                        // LD (HL),L
+	codes[0x76] = &HaltOpCode{BaseOpCode: BaseOpCode{code: 0x76, length: 1}}                                                                     // HALT
+	codes[0x77] = &LdRegIntoMemOpCode{BaseOpCode: BaseOpCode{code: 0x77, length: 1}, r1: &c.HL, r2: &c.A}                                        // LD (HL),A
+	codes[0x78] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x78, length: 1}, r1: &c.A, r2: &c.B}                                       // LD A,B
+	codes[0x79] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x79, length: 1}, r1: &c.A, r2: &c.C}                                       // LD A,C
+	codes[0x7A] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x7A, length: 1}, r1: &c.A, r2: &c.D}                                       // LD A,D
+	codes[0x7B] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x7B, length: 1}, r1: &c.A, r2: &c.E}                                       // LD A,E
+	codes[0x7C] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x7C, length: 1}, r1: &c.A, r2: &c.H}                                       // LD A,H
+	codes[0x7D] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x7D, length: 1}, r1: &c.A, r2: &c.L}                                       // LD A,L
+	codes[0x7E] = &LdMemIntoRegOpCode{BaseOpCode: BaseOpCode{code: 0x7E, length: 1}, r1: &c.A, r2: &c.HL}                                        // LD A,(HL)
+	codes[0x7F] = &Ld8BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0x7F, length: 1}, r1: &c.A, r2: &c.A}                                       // LD A,A
+	codes[0x80] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x80, length: 1}, r1: &c.A, r2: &c.B, includeCarry: false}                      // ADD A,B
+	codes[0x81] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x81, length: 1}, r1: &c.A, r2: &c.C, includeCarry: false}                      // ADD A,C
+	codes[0x82] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x82, length: 1}, r1: &c.A, r2: &c.D, includeCarry: false}                      // ADD A,D
+	codes[0x83] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x83, length: 1}, r1: &c.A, r2: &c.E, includeCarry: false}                      // ADD A,E
+	codes[0x84] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x84, length: 1}, r1: &c.A, r2: &c.H, includeCarry: false}                      // ADD A,H
+	codes[0x85] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x85, length: 1}, r1: &c.A, r2: &c.L, includeCarry: false}                      // ADD A,L
+	codes[0x86] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x86, length: 1}, r1: &c.A, r2: &c.HL, includeCarry: false}                     // ADD A,(HL)
+	codes[0x87] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x87, length: 1}, r1: &c.A, r2: &c.A, includeCarry: false}                      // ADD A,A
+	codes[0x88] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x88, length: 1}, r1: &c.A, r2: &c.B, includeCarry: true}                       // ADC A,B
+	codes[0x89] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x89, length: 1}, r1: &c.A, r2: &c.C, includeCarry: true}                       // ADC A,C
+	codes[0x8A] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x8A, length: 1}, r1: &c.A, r2: &c.D, includeCarry: true}                       // ADC A,D
+	codes[0x8B] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x8B, length: 1}, r1: &c.A, r2: &c.E, includeCarry: true}                       // ADC A,E
+	codes[0x8C] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x8C, length: 1}, r1: &c.A, r2: &c.H, includeCarry: true}                       // ADC A,H
+	codes[0x8D] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x8D, length: 1}, r1: &c.A, r2: &c.L, includeCarry: true}                       // ADC A,L
+	codes[0x8E] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x8E, length: 1}, r1: &c.A, r2: &c.HL, includeCarry: true}                      // ADC A,(HL)
+	codes[0x8F] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x8F, length: 1}, r1: &c.A, r2: &c.A, includeCarry: true}                       // ADC A,A
+	codes[0x90] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x90, length: 1}, r1: &c.A, r2: &c.B, includeCarry: false}                      // SUB B
+	codes[0x91] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x91, length: 1}, r1: &c.A, r2: &c.C, includeCarry: false}                      // SUB C
+	codes[0x92] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x92, length: 1}, r1: &c.A, r2: &c.D, includeCarry: false}                      // SUB D
+	codes[0x93] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x93, length: 1}, r1: &c.A, r2: &c.E, includeCarry: false}                      // SUB E
+	codes[0x94] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x94, length: 1}, r1: &c.A, r2: &c.H, includeCarry: false}                      // SUB H
+	codes[0x95] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x95, length: 1}, r1: &c.A, r2: &c.L, includeCarry: false}                      // SUB L
+	codes[0x96] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x96, length: 1}, r1: &c.A, r2: &c.HL, includeCarry: false}                     // SUB (HL)
+	codes[0x97] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x97, length: 1}, r1: &c.A, r2: &c.A, includeCarry: false}                      // SUB A
+	codes[0x98] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x98, length: 1}, r1: &c.A, r2: &c.B, includeCarry: true}                       // SBC A,B
+	codes[0x99] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x99, length: 1}, r1: &c.A, r2: &c.C, includeCarry: true}                       // SBC A,C
+	codes[0x9A] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x9A, length: 1}, r1: &c.A, r2: &c.D, includeCarry: true}                       // SBC A,D
+	codes[0x9B] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x9B, length: 1}, r1: &c.A, r2: &c.E, includeCarry: true}                       // SBC A,E
+	codes[0x9C] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x9C, length: 1}, r1: &c.A, r2: &c.H, includeCarry: true}                       // SBC A,H
+	codes[0x9D] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x9D, length: 1}, r1: &c.A, r2: &c.L, includeCarry: true}                       // SBC A,L
+	codes[0x9E] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x9E, length: 1}, r1: &c.A, r2: &c.HL, includeCarry: true}                      // SBC A,(HL)
+	codes[0x9F] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0x9F, length: 1}, r1: &c.A, r2: &c.A, includeCarry: true}                       // SBC A,A
+	codes[0xA0] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xA0, length: 1}, target: &c.A, source: &c.B, operation: AND}                      // AND B
+	codes[0xA1] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xA1, length: 1}, target: &c.A, source: &c.C, operation: AND}                      // AND C
+	codes[0xA2] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xA2, length: 1}, target: &c.A, source: &c.D, operation: AND}                      // AND D
+	codes[0xA3] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xA3, length: 1}, target: &c.A, source: &c.E, operation: AND}                      // AND E
+	codes[0xA4] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xA4, length: 1}, target: &c.A, source: &c.H, operation: AND}                      // AND H
+	codes[0xA5] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xA5, length: 1}, target: &c.A, source: &c.L, operation: AND}                      // AND L
+	codes[0xA6] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xA6, length: 1}, target: &c.A, source: &c.HL, operation: AND}                     // AND (HL)
+	codes[0xA7] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xA7, length: 1}, target: &c.A, source: &c.A, operation: AND}                      // AND A
+	codes[0xA8] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xA8, length: 1}, target: &c.A, source: &c.B, operation: XOR}                      // XOR B
+	codes[0xA9] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xA9, length: 1}, target: &c.A, source: &c.C, operation: XOR}                      // XOR C
+	codes[0xAA] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xAA, length: 1}, target: &c.A, source: &c.D, operation: XOR}                      // XOR D
+	codes[0xAB] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xAB, length: 1}, target: &c.A, source: &c.E, operation: XOR}                      // XOR E
+	codes[0xAC] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xAC, length: 1}, target: &c.A, source: &c.H, operation: XOR}                      // XOR H
+	codes[0xAD] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xAD, length: 1}, target: &c.A, source: &c.L, operation: XOR}                      // XOR L
+	codes[0xAE] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xAE, length: 1}, target: &c.A, source: &c.HL, operation: XOR}                     // XOR (HL)
+	codes[0xAF] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xAF, length: 1}, target: &c.A, source: &c.A, operation: XOR}                      // XOR A
+	codes[0xB0] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xB0, length: 1}, target: &c.A, source: &c.B, operation: OR}                       // OR B
+	codes[0xB1] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xB1, length: 1}, target: &c.A, source: &c.C, operation: OR}                       // OR C
+	codes[0xB2] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xB2, length: 1}, target: &c.A, source: &c.D, operation: OR}                       // OR D
+	codes[0xB3] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xB3, length: 1}, target: &c.A, source: &c.E, operation: OR}                       // OR E
+	codes[0xB4] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xB4, length: 1}, target: &c.A, source: &c.H, operation: OR}                       // OR H
+	codes[0xB5] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xB5, length: 1}, target: &c.A, source: &c.L, operation: OR}                       // OR L
+	codes[0xB6] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xB6, length: 1}, target: &c.A, source: &c.HL, operation: OR}                      // OR (HL)
+	codes[0xB7] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xB7, length: 1}, target: &c.A, source: &c.A, operation: OR}                       // OR A
+	codes[0xB8] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xB8, length: 1}, target: &c.A, source: &c.B, operation: CP}                       // CP B
+	codes[0xB9] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xB9, length: 1}, target: &c.A, source: &c.C, operation: CP}                       // CP C
+	codes[0xBA] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xBA, length: 1}, target: &c.A, source: &c.D, operation: CP}                       // CP D
+	codes[0xBB] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xBB, length: 1}, target: &c.A, source: &c.E, operation: CP}                       // CP E
+	codes[0xBC] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xBC, length: 1}, target: &c.A, source: &c.H, operation: CP}                       // CP H
+	codes[0xBD] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xBD, length: 1}, target: &c.A, source: &c.L, operation: CP}                       // CP L
+	codes[0xBE] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xBE, length: 1}, target: &c.A, source: &c.HL, operation: CP}                      // CP (HL)
+	codes[0xBF] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xBF, length: 1}, target: &c.A, source: &c.A, operation: CP}                       // CP A
+	codes[0xC0] = &ReturnOpCode{BaseOpCode: BaseOpCode{code: 0xC0, length: 1}, condition: NotZero}                                               // RET NZ
+	codes[0xC1] = &PopOpCode{BaseOpCode: BaseOpCode{code: 0xC1, length: 1}, r1: &c.BC}                                                           // POP BC
+	codes[0xC2] = &JumpOpCode{BaseOpCode: BaseOpCode{code: 0xC2, length: 3}, condition: NotZero}                                                 // JP NZ,a16
+	codes[0xC3] = &JumpOpCode{BaseOpCode: BaseOpCode{code: 0xC3, length: 3}, condition: Always}                                                  // JP a16
+	codes[0xC4] = &CallOpCode{BaseOpCode: BaseOpCode{code: 0xC4, length: 3}, condition: NotZero}                                                 // CALL NZ,a16
+	codes[0xC5] = &PushOpCode{BaseOpCode: BaseOpCode{code: 0xC5, length: 1}, r1: &c.BC}                                                          // PUSH BC
+	codes[0xC6] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0xC6, length: 2}, r1: &c.A, r2: &ImmediateByte{pc: &c.PC}, includeCarry: false} // ADD A,d8
+	codes[0xC7] = &RestartOpCode{BaseOpCode: BaseOpCode{code: 0xC7, length: 1}, target: 0x00}                                                    // RST 00H
+	codes[0xC8] = &ReturnOpCode{BaseOpCode: BaseOpCode{code: 0xC8, length: 1}, condition: Zero}                                                  // RET Z
+	codes[0xC9] = &ReturnOpCode{BaseOpCode: BaseOpCode{code: 0xC9, length: 1}, condition: Always}                                                // RET
+	codes[0xCA] = &JumpOpCode{BaseOpCode: BaseOpCode{code: 0xCA, length: 3}, condition: Zero}                                                    // JP Z,a16
+	codes[0xCB] = &CBPrefixOpCode{BaseOpCode: BaseOpCode{code: 0xCB, length: 2}}                                                                 // PREFIX CB
+	codes[0xCC] = &CallOpCode{BaseOpCode: BaseOpCode{code: 0xCC, length: 3}, condition: Zero}                                                    // CALL Z,a16
+	codes[0xCD] = &CallOpCode{BaseOpCode: BaseOpCode{code: 0xCD, length: 3}, condition: Always}                                                  // CALL a16
+	codes[0xCE] = &Add8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0xCE, length: 2}, r1: &c.A, r2: &ImmediateByte{pc: &c.PC}, includeCarry: true}  // ADC A,d8
+	codes[0xCF] = &RestartOpCode{BaseOpCode: BaseOpCode{code: 0xCF, length: 1}, target: 0x08}                                                    // RST 08H
+	codes[0xD0] = &ReturnOpCode{BaseOpCode: BaseOpCode{code: 0xD0, length: 1}, condition: NoCarry}                                               // RET NC
+	codes[0xD1] = &PopOpCode{BaseOpCode: BaseOpCode{code: 0xD1, length: 1}, r1: &c.DE}                                                           // POP DE
+	codes[0xD2] = &JumpOpCode{BaseOpCode: BaseOpCode{code: 0xD2, length: 3}, condition: NoCarry}                                                 // JP NC,a16
+	codes[0xD3] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xD3, length: 1}}                                                                  // ILLEGAL
+	codes[0xD4] = &CallOpCode{BaseOpCode: BaseOpCode{code: 0xD4, length: 3}, condition: NoCarry}                                                 // CALL NC,a16
+	codes[0xD5] = &PushOpCode{BaseOpCode: BaseOpCode{code: 0xD5, length: 1}, r1: &c.DE}                                                          // PUSH DE
+	codes[0xD6] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0xD6, length: 2}, r1: &c.A, r2: &ImmediateByte{pc: &c.PC}, includeCarry: false} // SUB d8
+	codes[0xD7] = &RestartOpCode{BaseOpCode: BaseOpCode{code: 0xD7, length: 1}, target: 0x10}                                                    // RST 10H
+	codes[0xD8] = &ReturnOpCode{BaseOpCode: BaseOpCode{code: 0xD8, length: 1}, condition: Carry}                                                 // RET C
+	codes[0xD9] = &ReturnOpCode{BaseOpCode: BaseOpCode{code: 0xD9, length: 1}, condition: Always, enableInterrupts: true}                        // RETI
+	codes[0xDA] = &JumpOpCode{BaseOpCode: BaseOpCode{code: 0xDA, length: 3}, condition: Carry}                                                   // JP C,a16
+	codes[0xDB] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xDB, length: 1}}                                                                  // ILLEGAL
+	codes[0xDC] = &CallOpCode{BaseOpCode: BaseOpCode{code: 0xDC, length: 3}, condition: Carry}                                                   // CALL C,a16
+	codes[0xDD] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xDD, length: 1}}                                                                  // ILLEGAL
+	codes[0xDE] = &Sub8BitRegOpCode{BaseOpCode: BaseOpCode{code: 0xDE, length: 2}, r1: &c.A, r2: &ImmediateByte{pc: &c.PC}, includeCarry: true}  // SBC A,d8
+	codes[0xDF] = &RestartOpCode{BaseOpCode: BaseOpCode{code: 0xDF, length: 1}, target: 0x18}                                                    // RST 18H
+	codes[0xE0] = &LdByteSourceOpCode{BaseOpCode: BaseOpCode{code: 0xE0, length: 2}, r1: &HighPageAddress{pc: &c.PC}, r2: &c.A, highPage: true}  // LDH (a8),A
+	codes[0xE1] = &PopOpCode{BaseOpCode: BaseOpCode{code: 0xE1, length: 1}, r1: &c.HL}                                                           // POP HL
+	codes[0xE2] = &LdByteSourceOpCode{BaseOpCode: BaseOpCode{code: 0xE2, length: 1}, r1: &HighPageAddress{pc: &c.PC, offset: &c.C}, r2: &c.A}    // LD (C),A
+	codes[0xE3] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xE3, length: 1}}                                                                  // ILLEGAL
+	codes[0xE4] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xE4, length: 1}}                                                                  // ILLEGAL
+	codes[0xE5] = &PushOpCode{BaseOpCode: BaseOpCode{code: 0xE5, length: 1}, r1: &c.HL}                                                          // PUSH HL
+	codes[0xE6] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xE6, length: 2}, target: &c.A, source: &ImmediateByte{pc: &c.PC}, operation: AND} // AND d8
+	codes[0xE7] = &RestartOpCode{BaseOpCode: BaseOpCode{code: 0xE7, length: 1}, target: 0x20}                                                    // RST 20H
+	codes[0xE8] = &AddSPOffsetOpCode{BaseOpCode: BaseOpCode{code: 0xE8, length: 2}, r1: &c.SP}                                                   // ADD SP,r8
+	codes[0xE9] = &JumpRegisterOpCode{BaseOpCode: BaseOpCode{code: 0xE9, length: 1}, r1: &c.HL}                                                  // JP (HL)
+	codes[0xEA] = &LdByteSourceOpCode{BaseOpCode: BaseOpCode{code: 0xEA, length: 3}, r1: &ImmediateAddress{pc: &c.PC}, r2: &c.A}                 // LD (a16),A
+	codes[0xEB] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xEB, length: 1}}                                                                  // ILLEGAL
+	codes[0xEC] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xEC, length: 1}}                                                                  // ILLEGAL
+	codes[0xED] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xED, length: 1}}                                                                  // ILLEGAL
+	codes[0xEE] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xEE, length: 2}, target: &c.A, source: &ImmediateByte{pc: &c.PC}, operation: XOR} // XOR d8
+	codes[0xEF] = &RestartOpCode{BaseOpCode: BaseOpCode{code: 0xEF, length: 1}, target: 0x28}                                                    // RST 28H
+	codes[0xF0] = &LdByteSourceOpCode{BaseOpCode: BaseOpCode{code: 0xF0, length: 2}, r1: &c.A, r2: &HighPageAddress{pc: &c.PC}, highPage: true}  // LDH A,(a8)
+	codes[0xF1] = &PopOpCode{BaseOpCode: BaseOpCode{code: 0xF1, length: 1}, r1: &c.AF}                                                           // POP AF
+	codes[0xF2] = &LdByteSourceOpCode{BaseOpCode: BaseOpCode{code: 0xF2, length: 1}, r1: &c.A, r2: &HighPageAddress{pc: &c.PC, offset: &c.C}}    // LD A,(C)
+	codes[0xF3] = &InterruptMasterOpCode{BaseOpCode: BaseOpCode{code: 0xF3, length: 1}, enable: false}                                           // DI
+	codes[0xF4] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xF4, length: 1}}                                                                  // ILLEGAL
+	codes[0xF5] = &PushOpCode{BaseOpCode: BaseOpCode{code: 0xF5, length: 1}, r1: &c.AF}                                                          // PUSH AF
+	codes[0xF6] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xF6, length: 2}, target: &c.A, source: &ImmediateByte{pc: &c.PC}, operation: OR}  // OR d8
+	codes[0xF7] = &RestartOpCode{BaseOpCode: BaseOpCode{code: 0xF7, length: 1}, target: 0x30}                                                    // RST 30H
+	codes[0xF8] = &LdSPOffsetOpCode{BaseOpCode: BaseOpCode{code: 0xF8, length: 2}, r1: &c.HL, r2: &c.SP}                                         // LD HL,SP+r8
+	codes[0xF9] = &Ld16BitRegisterOpCode{BaseOpCode: BaseOpCode{code: 0xF9, length: 1}, r1: &c.SP, r2: &c.HL}                                    // LD SP,HL
+	codes[0xFA] = &LdByteSourceOpCode{BaseOpCode: BaseOpCode{code: 0xFA, length: 3}, r1: &c.A, r2: &ImmediateAddress{pc: &c.PC}}                 // LD A,(a16)
+	codes[0xFB] = &InterruptMasterOpCode{BaseOpCode: BaseOpCode{code: 0xFB, length: 1}, enable: true}                                            // EI
+	codes[0xFC] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xFC, length: 1}}                                                                  // ILLEGAL
+	codes[0xFD] = &IllegalOpCode{BaseOpCode: BaseOpCode{code: 0xFD, length: 1}}                                                                  // ILLEGAL
+	codes[0xFE] = &Logical8BitOp{BaseOpCode: BaseOpCode{code: 0xFE, length: 2}, target: &c.A, source: &ImmediateByte{pc: &c.PC}, operation: CP}  // CP d8
+	codes[0xFF] = &RestartOpCode{BaseOpCode: BaseOpCode{code: 0xFF, length: 1}, target: 0x38}                                                    // RST 38H
+	return codes
+}
+
+func (c *Cpu) generateCBOpCodes() map[byte]OpCode {
+	codes := make(map[byte]OpCode)
+	codes[0x00] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x00, length: 2}, r1: &c.B, direction: Left, circular: true, isCB: true}    // RLC B
+	codes[0x01] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x01, length: 2}, r1: &c.C, direction: Left, circular: true, isCB: true}    // RLC C
+	codes[0x02] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x02, length: 2}, r1: &c.D, direction: Left, circular: true, isCB: true}    // RLC D
+	codes[0x03] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x03, length: 2}, r1: &c.E, direction: Left, circular: true, isCB: true}    // RLC E
+	codes[0x04] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x04, length: 2}, r1: &c.H, direction: Left, circular: true, isCB: true}    // RLC H
+	codes[0x05] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x05, length: 2}, r1: &c.L, direction: Left, circular: true, isCB: true}    // RLC L
+	codes[0x06] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x06, length: 2}, r1: &c.HL, direction: Left, circular: true, isCB: true}   // RLC (HL)
+	codes[0x07] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x07, length: 2}, r1: &c.A, direction: Left, circular: true, isCB: true}    // RLC A
+	codes[0x08] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x08, length: 2}, r1: &c.B, direction: Right, circular: true, isCB: true}   // RRC B
+	codes[0x09] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x09, length: 2}, r1: &c.C, direction: Right, circular: true, isCB: true}   // RRC C
+	codes[0x0A] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x0A, length: 2}, r1: &c.D, direction: Right, circular: true, isCB: true}   // RRC D
+	codes[0x0B] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x0B, length: 2}, r1: &c.E, direction: Right, circular: true, isCB: true}   // RRC E
+	codes[0x0C] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x0C, length: 2}, r1: &c.H, direction: Right, circular: true, isCB: true}   // RRC H
+	codes[0x0D] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x0D, length: 2}, r1: &c.L, direction: Right, circular: true, isCB: true}   // RRC L
+	codes[0x0E] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x0E, length: 2}, r1: &c.HL, direction: Right, circular: true, isCB: true}  // RRC (HL)
+	codes[0x0F] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x0F, length: 2}, r1: &c.A, direction: Right, circular: true, isCB: true}   // RRC A
+	codes[0x10] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x10, length: 2}, r1: &c.B, direction: Left, circular: false, isCB: true}   // RL B
+	codes[0x11] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x11, length: 2}, r1: &c.C, direction: Left, circular: false, isCB: true}   // RL C
+	codes[0x12] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x12, length: 2}, r1: &c.D, direction: Left, circular: false, isCB: true}   // RL D
+	codes[0x13] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x13, length: 2}, r1: &c.E, direction: Left, circular: false, isCB: true}   // RL E
+	codes[0x14] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x14, length: 2}, r1: &c.H, direction: Left, circular: false, isCB: true}   // RL H
+	codes[0x15] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x15, length: 2}, r1: &c.L, direction: Left, circular: false, isCB: true}   // RL L
+	codes[0x16] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x16, length: 2}, r1: &c.HL, direction: Left, circular: false, isCB: true}  // RL (HL)
+	codes[0x17] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x17, length: 2}, r1: &c.A, direction: Left, circular: false, isCB: true}   // RL A
+	codes[0x18] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x18, length: 2}, r1: &c.B, direction: Right, circular: false, isCB: true}  // RR B
+	codes[0x19] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x19, length: 2}, r1: &c.C, direction: Right, circular: false, isCB: true}  // RR C
+	codes[0x1A] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x1A, length: 2}, r1: &c.D, direction: Right, circular: false, isCB: true}  // RR D
+	codes[0x1B] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x1B, length: 2}, r1: &c.E, direction: Right, circular: false, isCB: true}  // RR E
+	codes[0x1C] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x1C, length: 2}, r1: &c.H, direction: Right, circular: false, isCB: true}  // RR H
+	codes[0x1D] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x1D, length: 2}, r1: &c.L, direction: Right, circular: false, isCB: true}  // RR L
+	codes[0x1E] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x1E, length: 2}, r1: &c.HL, direction: Right, circular: false, isCB: true} // RR (HL)
+	codes[0x1F] = &RotateOpCode{BaseOpCode: BaseOpCode{code: 0x1F, length: 2}, r1: &c.A, direction: Right, circular: false, isCB: true}  // RR A
+	codes[0x20] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x20, length: 2}, r1: &c.B, direction: Left}                                 // SLA B
+	codes[0x21] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x21, length: 2}, r1: &c.C, direction: Left}                                 // SLA C
+	codes[0x22] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x22, length: 2}, r1: &c.D, direction: Left}                                 // SLA D
+	codes[0x23] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x23, length: 2}, r1: &c.E, direction: Left}                                 // SLA E
+	codes[0x24] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x24, length: 2}, r1: &c.H, direction: Left}                                 // SLA H
+	codes[0x25] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x25, length: 2}, r1: &c.L, direction: Left}                                 // SLA L
+	codes[0x26] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x26, length: 2}, r1: &c.HL, direction: Left}                                // SLA (HL)
+	codes[0x27] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x27, length: 2}, r1: &c.A, direction: Left}                                 // SLA A
+	codes[0x28] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x28, length: 2}, r1: &c.B, direction: Right, arithmetic: true}              // SRA B
+	codes[0x29] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x29, length: 2}, r1: &c.C, direction: Right, arithmetic: true}              // SRA C
+	codes[0x2A] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x2A, length: 2}, r1: &c.D, direction: Right, arithmetic: true}              // SRA D
+	codes[0x2B] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x2B, length: 2}, r1: &c.E, direction: Right, arithmetic: true}              // SRA E
+	codes[0x2C] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x2C, length: 2}, r1: &c.H, direction: Right, arithmetic: true}              // SRA H
+	codes[0x2D] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x2D, length: 2}, r1: &c.L, direction: Right, arithmetic: true}              // SRA L
+	codes[0x2E] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x2E, length: 2}, r1: &c.HL, direction: Right, arithmetic: true}             // SRA (HL)
+	codes[0x2F] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x2F, length: 2}, r1: &c.A, direction: Right, arithmetic: true}              // SRA A
+	codes[0x30] = &SwapOpCode{BaseOpCode: BaseOpCode{code: 0x30, length: 2}, r1: &c.B}                                                   // SWAP B
+	codes[0x31] = &SwapOpCode{BaseOpCode: BaseOpCode{code: 0x31, length: 2}, r1: &c.C}                                                   // SWAP C
+	codes[0x32] = &SwapOpCode{BaseOpCode: BaseOpCode{code: 0x32, length: 2}, r1: &c.D}                                                   // SWAP D
+	codes[0x33] = &SwapOpCode{BaseOpCode: BaseOpCode{code: 0x33, length: 2}, r1: &c.E}                                                   // SWAP E
+	codes[0x34] = &SwapOpCode{BaseOpCode: BaseOpCode{code: 0x34, length: 2}, r1: &c.H}                                                   // SWAP H
+	codes[0x35] = &SwapOpCode{BaseOpCode: BaseOpCode{code: 0x35, length: 2}, r1: &c.L}                                                   // SWAP L
+	codes[0x36] = &SwapOpCode{BaseOpCode: BaseOpCode{code: 0x36, length: 2}, r1: &c.HL}                                                  // SWAP (HL)
+	codes[0x37] = &SwapOpCode{BaseOpCode: BaseOpCode{code: 0x37, length: 2}, r1: &c.A}                                                   // SWAP A
+	codes[0x38] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x38, length: 2}, r1: &c.B, direction: Right, arithmetic: false}             // SRL B
+	codes[0x39] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x39, length: 2}, r1: &c.C, direction: Right, arithmetic: false}             // SRL C
+	codes[0x3A] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x3A, length: 2}, r1: &c.D, direction: Right, arithmetic: false}             // SRL D
+	codes[0x3B] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x3B, length: 2}, r1: &c.E, direction: Right, arithmetic: false}             // SRL E
+	codes[0x3C] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x3C, length: 2}, r1: &c.H, direction: Right, arithmetic: false}             // SRL H
+	codes[0x3D] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x3D, length: 2}, r1: &c.L, direction: Right, arithmetic: false}             // SRL L
+	codes[0x3E] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x3E, length: 2}, r1: &c.HL, direction: Right, arithmetic: false}            // SRL (HL)
+	codes[0x3F] = &ShiftOpCode{BaseOpCode: BaseOpCode{code: 0x3F, length: 2}, r1: &c.A, direction: Right, arithmetic: false}             // SRL A
+	codes[0x40] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x40, length: 2}, r1: &c.B, bit: 0}                                        // BIT 0,B
+	codes[0x41] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x41, length: 2}, r1: &c.C, bit: 0}                                        // BIT 0,C
+	codes[0x42] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x42, length: 2}, r1: &c.D, bit: 0}                                        // BIT 0,D
+	codes[0x43] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x43, length: 2}, r1: &c.E, bit: 0}                                        // BIT 0,E
+	codes[0x44] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x44, length: 2}, r1: &c.H, bit: 0}                                        // BIT 0,H
+	codes[0x45] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x45, length: 2}, r1: &c.L, bit: 0}                                        // BIT 0,L
+	codes[0x46] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x46, length: 2}, r1: &c.HL, bit: 0}                                       // BIT 0,(HL)
+	codes[0x47] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x47, length: 2}, r1: &c.A, bit: 0}                                        // BIT 0,A
+	codes[0x48] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x48, length: 2}, r1: &c.B, bit: 1}                                        // BIT 1,B
+	codes[0x49] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x49, length: 2}, r1: &c.C, bit: 1}                                        // BIT 1,C
+	codes[0x4A] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x4A, length: 2}, r1: &c.D, bit: 1}                                        // BIT 1,D
+	codes[0x4B] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x4B, length: 2}, r1: &c.E, bit: 1}                                        // BIT 1,E
+	codes[0x4C] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x4C, length: 2}, r1: &c.H, bit: 1}                                        // BIT 1,H
+	codes[0x4D] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x4D, length: 2}, r1: &c.L, bit: 1}                                        // BIT 1,L
+	codes[0x4E] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x4E, length: 2}, r1: &c.HL, bit: 1}                                       // BIT 1,(HL)
+	codes[0x4F] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x4F, length: 2}, r1: &c.A, bit: 1}                                        // BIT 1,A
+	codes[0x50] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x50, length: 2}, r1: &c.B, bit: 2}                                        // BIT 2,B
+	codes[0x51] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x51, length: 2}, r1: &c.C, bit: 2}                                        // BIT 2,C
+	codes[0x52] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x52, length: 2}, r1: &c.D, bit: 2}                                        // BIT 2,D
+	codes[0x53] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x53, length: 2}, r1: &c.E, bit: 2}                                        // BIT 2,E
+	codes[0x54] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x54, length: 2}, r1: &c.H, bit: 2}                                        // BIT 2,H
+	codes[0x55] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x55, length: 2}, r1: &c.L, bit: 2}                                        // BIT 2,L
+	codes[0x56] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x56, length: 2}, r1: &c.HL, bit: 2}                                       // BIT 2,(HL)
+	codes[0x57] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x57, length: 2}, r1: &c.A, bit: 2}                                        // BIT 2,A
+	codes[0x58] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x58, length: 2}, r1: &c.B, bit: 3}                                        // BIT 3,B
+	codes[0x59] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x59, length: 2}, r1: &c.C, bit: 3}                                        // BIT 3,C
+	codes[0x5A] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x5A, length: 2}, r1: &c.D, bit: 3}                                        // BIT 3,D
+	codes[0x5B] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x5B, length: 2}, r1: &c.E, bit: 3}                                        // BIT 3,E
+	codes[0x5C] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x5C, length: 2}, r1: &c.H, bit: 3}                                        // BIT 3,H
+	codes[0x5D] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x5D, length: 2}, r1: &c.L, bit: 3}                                        // BIT 3,L
+	codes[0x5E] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x5E, length: 2}, r1: &c.HL, bit: 3}                                       // BIT 3,(HL)
+	codes[0x5F] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x5F, length: 2}, r1: &c.A, bit: 3}                                        // BIT 3,A
+	codes[0x60] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x60, length: 2}, r1: &c.B, bit: 4}                                        // BIT 4,B
+	codes[0x61] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x61, length: 2}, r1: &c.C, bit: 4}                                        // BIT 4,C
+	codes[0x62] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x62, length: 2}, r1: &c.D, bit: 4}                                        // BIT 4,D
+	codes[0x63] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x63, length: 2}, r1: &c.E, bit: 4}                                        // BIT 4,E
+	codes[0x64] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x64, length: 2}, r1: &c.H, bit: 4}                                        // BIT 4,H
+	codes[0x65] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x65, length: 2}, r1: &c.L, bit: 4}                                        // BIT 4,L
+	codes[0x66] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x66, length: 2}, r1: &c.HL, bit: 4}                                       // BIT 4,(HL)
+	codes[0x67] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x67, length: 2}, r1: &c.A, bit: 4}                                        // BIT 4,A
+	codes[0x68] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x68, length: 2}, r1: &c.B, bit: 5}                                        // BIT 5,B
+	codes[0x69] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x69, length: 2}, r1: &c.C, bit: 5}                                        // BIT 5,C
+	codes[0x6A] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x6A, length: 2}, r1: &c.D, bit: 5}                                        // BIT 5,D
+	codes[0x6B] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x6B, length: 2}, r1: &c.E, bit: 5}                                        // BIT 5,E
+	codes[0x6C] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x6C, length: 2}, r1: &c.H, bit: 5}                                        // BIT 5,H
+	codes[0x6D] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x6D, length: 2}, r1: &c.L, bit: 5}                                        // BIT 5,L
+	codes[0x6E] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x6E, length: 2}, r1: &c.HL, bit: 5}                                       // BIT 5,(HL)
+	codes[0x6F] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x6F, length: 2}, r1: &c.A, bit: 5}                                        // BIT 5,A
+	codes[0x70] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x70, length: 2}, r1: &c.B, bit: 6}                                        // BIT 6,B
+	codes[0x71] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x71, length: 2}, r1: &c.C, bit: 6}                                        // BIT 6,C
+	codes[0x72] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x72, length: 2}, r1: &c.D, bit: 6}                                        // BIT 6,D
+	codes[0x73] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x73, length: 2}, r1: &c.E, bit: 6}                                        // BIT 6,E
+	codes[0x74] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x74, length: 2}, r1: &c.H, bit: 6}                                        // BIT 6,H
+	codes[0x75] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x75, length: 2}, r1: &c.L, bit: 6}                                        // BIT 6,L
+	codes[0x76] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x76, length: 2}, r1: &c.HL, bit: 6}                                       // BIT 6,(HL)
+	codes[0x77] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x77, length: 2}, r1: &c.A, bit: 6}                                        // BIT 6,A
+	codes[0x78] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x78, length: 2}, r1: &c.B, bit: 7}                                        // BIT 7,B
+	codes[0x79] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x79, length: 2}, r1: &c.C, bit: 7}                                        // BIT 7,C
+	codes[0x7A] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x7A, length: 2}, r1: &c.D, bit: 7}                                        // BIT 7,D
+	codes[0x7B] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x7B, length: 2}, r1: &c.E, bit: 7}                                        // BIT 7,E
+	codes[0x7C] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x7C, length: 2}, r1: &c.H, bit: 7}                                        // BIT 7,H
+	codes[0x7D] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x7D, length: 2}, r1: &c.L, bit: 7}                                        // BIT 7,L
+	codes[0x7E] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x7E, length: 2}, r1: &c.HL, bit: 7}                                       // BIT 7,(HL)
+	codes[0x7F] = &TestBitOpCode{BaseOpCode: BaseOpCode{code: 0x7F, length: 2}, r1: &c.A, bit: 7}                                        // BIT 7,A
+	codes[0x80] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x80, length: 2}, r1: &c.B, bit: 0, value: false}                           // RES 0,B
+	codes[0x81] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x81, length: 2}, r1: &c.C, bit: 0, value: false}                           // RES 0,C
+	codes[0x82] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x82, length: 2}, r1: &c.D, bit: 0, value: false}                           // RES 0,D
+	codes[0x83] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x83, length: 2}, r1: &c.E, bit: 0, value: false}                           // RES 0,E
+	codes[0x84] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x84, length: 2}, r1: &c.H, bit: 0, value: false}                           // RES 0,H
+	codes[0x85] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x85, length: 2}, r1: &c.L, bit: 0, value: false}                           // RES 0,L
+	codes[0x86] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x86, length: 2}, r1: &c.HL, bit: 0, value: false}                          // RES 0,(HL)
+	codes[0x87] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x87, length: 2}, r1: &c.A, bit: 0, value: false}                           // RES 0,A
+	codes[0x88] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x88, length: 2}, r1: &c.B, bit: 1, value: false}                           // RES 1,B
+	codes[0x89] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x89, length: 2}, r1: &c.C, bit: 1, value: false}                           // RES 1,C
+	codes[0x8A] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x8A, length: 2}, r1: &c.D, bit: 1, value: false}                           // RES 1,D
+	codes[0x8B] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x8B, length: 2}, r1: &c.E, bit: 1, value: false}                           // RES 1,E
+	codes[0x8C] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x8C, length: 2}, r1: &c.H, bit: 1, value: false}                           // RES 1,H
+	codes[0x8D] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x8D, length: 2}, r1: &c.L, bit: 1, value: false}                           // RES 1,L
+	codes[0x8E] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x8E, length: 2}, r1: &c.HL, bit: 1, value: false}                          // RES 1,(HL)
+	codes[0x8F] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x8F, length: 2}, r1: &c.A, bit: 1, value: false}                           // RES 1,A
+	codes[0x90] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x90, length: 2}, r1: &c.B, bit: 2, value: false}                           // RES 2,B
+	codes[0x91] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x91, length: 2}, r1: &c.C, bit: 2, value: false}                           // RES 2,C
+	codes[0x92] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x92, length: 2}, r1: &c.D, bit: 2, value: false}                           // RES 2,D
+	codes[0x93] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x93, length: 2}, r1: &c.E, bit: 2, value: false}                           // RES 2,E
+	codes[0x94] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x94, length: 2}, r1: &c.H, bit: 2, value: false}                           // RES 2,H
+	codes[0x95] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x95, length: 2}, r1: &c.L, bit: 2, value: false}                           // RES 2,L
+	codes[0x96] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x96, length: 2}, r1: &c.HL, bit: 2, value: false}                          // RES 2,(HL)
+	codes[0x97] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x97, length: 2}, r1: &c.A, bit: 2, value: false}                           // RES 2,A
+	codes[0x98] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x98, length: 2}, r1: &c.B, bit: 3, value: false}                           // RES 3,B
+	codes[0x99] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x99, length: 2}, r1: &c.C, bit: 3, value: false}                           // RES 3,C
+	codes[0x9A] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x9A, length: 2}, r1: &c.D, bit: 3, value: false}                           // RES 3,D
+	codes[0x9B] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x9B, length: 2}, r1: &c.E, bit: 3, value: false}                           // RES 3,E
+	codes[0x9C] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x9C, length: 2}, r1: &c.H, bit: 3, value: false}                           // RES 3,H
+	codes[0x9D] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x9D, length: 2}, r1: &c.L, bit: 3, value: false}                           // RES 3,L
+	codes[0x9E] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x9E, length: 2}, r1: &c.HL, bit: 3, value: false}                          // RES 3,(HL)
+	codes[0x9F] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0x9F, length: 2}, r1: &c.A, bit: 3, value: false}                           // RES 3,A
+	codes[0xA0] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xA0, length: 2}, r1: &c.B, bit: 4, value: false}                           // RES 4,B
+	codes[0xA1] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xA1, length: 2}, r1: &c.C, bit: 4, value: false}                           // RES 4,C
+	codes[0xA2] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xA2, length: 2}, r1: &c.D, bit: 4, value: false}                           // RES 4,D
+	codes[0xA3] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xA3, length: 2}, r1: &c.E, bit: 4, value: false}                           // RES 4,E
+	codes[0xA4] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xA4, length: 2}, r1: &c.H, bit: 4, value: false}                           // RES 4,H
+	codes[0xA5] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xA5, length: 2}, r1: &c.L, bit: 4, value: false}                           // RES 4,L
+	codes[0xA6] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xA6, length: 2}, r1: &c.HL, bit: 4, value: false}                          // RES 4,(HL)
+	codes[0xA7] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xA7, length: 2}, r1: &c.A, bit: 4, value: false}                           // RES 4,A
+	codes[0xA8] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xA8, length: 2}, r1: &c.B, bit: 5, value: false}                           // RES 5,B
+	codes[0xA9] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xA9, length: 2}, r1: &c.C, bit: 5, value: false}                           // RES 5,C
+	codes[0xAA] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xAA, length: 2}, r1: &c.D, bit: 5, value: false}                           // RES 5,D
+	codes[0xAB] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xAB, length: 2}, r1: &c.E, bit: 5, value: false}                           // RES 5,E
+	codes[0xAC] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xAC, length: 2}, r1: &c.H, bit: 5, value: false}                           // RES 5,H
+	codes[0xAD] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xAD, length: 2}, r1: &c.L, bit: 5, value: false}                           // RES 5,L
+	codes[0xAE] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xAE, length: 2}, r1: &c.HL, bit: 5, value: false}                          // RES 5,(HL)
+	codes[0xAF] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xAF, length: 2}, r1: &c.A, bit: 5, value: false}                           // RES 5,A
+	codes[0xB0] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xB0, length: 2}, r1: &c.B, bit: 6, value: false}                           // RES 6,B
+	codes[0xB1] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xB1, length: 2}, r1: &c.C, bit: 6, value: false}                           // RES 6,C
+	codes[0xB2] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xB2, length: 2}, r1: &c.D, bit: 6, value: false}                           // RES 6,D
+	codes[0xB3] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xB3, length: 2}, r1: &c.E, bit: 6, value: false}                           // RES 6,E
+	codes[0xB4] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xB4, length: 2}, r1: &c.H, bit: 6, value: false}                           // RES 6,H
+	codes[0xB5] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xB5, length: 2}, r1: &c.L, bit: 6, value: false}                           // RES 6,L
+	codes[0xB6] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xB6, length: 2}, r1: &c.HL, bit: 6, value: false}                          // RES 6,(HL)
+	codes[0xB7] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xB7, length: 2}, r1: &c.A, bit: 6, value: false}                           // RES 6,A
+	codes[0xB8] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xB8, length: 2}, r1: &c.B, bit: 7, value: false}                           // RES 7,B
+	codes[0xB9] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xB9, length: 2}, r1: &c.C, bit: 7, value: false}                           // RES 7,C
+	codes[0xBA] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xBA, length: 2}, r1: &c.D, bit: 7, value: false}                           // RES 7,D
+	codes[0xBB] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xBB, length: 2}, r1: &c.E, bit: 7, value: false}                           // RES 7,E
+	codes[0xBC] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xBC, length: 2}, r1: &c.H, bit: 7, value: false}                           // RES 7,H
+	codes[0xBD] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xBD, length: 2}, r1: &c.L, bit: 7, value: false}                           // RES 7,L
+	codes[0xBE] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xBE, length: 2}, r1: &c.HL, bit: 7, value: false}                          // RES 7,(HL)
+	codes[0xBF] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xBF, length: 2}, r1: &c.A, bit: 7, value: false}                           // RES 7,A
+	codes[0xC0] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xC0, length: 2}, r1: &c.B, bit: 0, value: true}                            // SET 0,B
+	codes[0xC1] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xC1, length: 2}, r1: &c.C, bit: 0, value: true}                            // SET 0,C
+	codes[0xC2] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xC2, length: 2}, r1: &c.D, bit: 0, value: true}                            // SET 0,D
+	codes[0xC3] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xC3, length: 2}, r1: &c.E, bit: 0, value: true}                            // SET 0,E
+	codes[0xC4] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xC4, length: 2}, r1: &c.H, bit: 0, value: true}                            // SET 0,H
+	codes[0xC5] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xC5, length: 2}, r1: &c.L, bit: 0, value: true}                            // SET 0,L
+	codes[0xC6] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xC6, length: 2}, r1: &c.HL, bit: 0, value: true}                           // SET 0,(HL)
+	codes[0xC7] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xC7, length: 2}, r1: &c.A, bit: 0, value: true}                            // SET 0,A
+	codes[0xC8] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xC8, length: 2}, r1: &c.B, bit: 1, value: true}                            // SET 1,B
+	codes[0xC9] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xC9, length: 2}, r1: &c.C, bit: 1, value: true}                            // SET 1,C
+	codes[0xCA] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xCA, length: 2}, r1: &c.D, bit: 1, value: true}                            // SET 1,D
+	codes[0xCB] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xCB, length: 2}, r1: &c.E, bit: 1, value: true}                            // SET 1,E
+	codes[0xCC] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xCC, length: 2}, r1: &c.H, bit: 1, value: true}                            // SET 1,H
+	codes[0xCD] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xCD, length: 2}, r1: &c.L, bit: 1, value: true}                            // SET 1,L
+	codes[0xCE] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xCE, length: 2}, r1: &c.HL, bit: 1, value: true}                           // SET 1,(HL)
+	codes[0xCF] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xCF, length: 2}, r1: &c.A, bit: 1, value: true}                            // SET 1,A
+	codes[0xD0] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xD0, length: 2}, r1: &c.B, bit: 2, value: true}                            // SET 2,B
+	codes[0xD1] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xD1, length: 2}, r1: &c.C, bit: 2, value: true}                            // SET 2,C
+	codes[0xD2] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xD2, length: 2}, r1: &c.D, bit: 2, value: true}                            // SET 2,D
+	codes[0xD3] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xD3, length: 2}, r1: &c.E, bit: 2, value: true}                            // SET 2,E
+	codes[0xD4] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xD4, length: 2}, r1: &c.H, bit: 2, value: true}                            // SET 2,H
+	codes[0xD5] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xD5, length: 2}, r1: &c.L, bit: 2, value: true}                            // SET 2,L
+	codes[0xD6] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xD6, length: 2}, r1: &c.HL, bit: 2, value: true}                           // SET 2,(HL)
+	codes[0xD7] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xD7, length: 2}, r1: &c.A, bit: 2, value: true}                            // SET 2,A
+	codes[0xD8] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xD8, length: 2}, r1: &c.B, bit: 3, value: true}                            // SET 3,B
+	codes[0xD9] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xD9, length: 2}, r1: &c.C, bit: 3, value: true}                            // SET 3,C
+	codes[0xDA] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xDA, length: 2}, r1: &c.D, bit: 3, value: true}                            // SET 3,D
+	codes[0xDB] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xDB, length: 2}, r1: &c.E, bit: 3, value: true}                            // SET 3,E
+	codes[0xDC] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xDC, length: 2}, r1: &c.H, bit: 3, value: true}                            // SET 3,H
+	codes[0xDD] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xDD, length: 2}, r1: &c.L, bit: 3, value: true}                            // SET 3,L
+	codes[0xDE] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xDE, length: 2}, r1: &c.HL, bit: 3, value: true}                           // SET 3,(HL)
+	codes[0xDF] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xDF, length: 2}, r1: &c.A, bit: 3, value: true}                            // SET 3,A
+	codes[0xE0] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xE0, length: 2}, r1: &c.B, bit: 4, value: true}                            // SET 4,B
+	codes[0xE1] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xE1, length: 2}, r1: &c.C, bit: 4, value: true}                            // SET 4,C
+	codes[0xE2] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xE2, length: 2}, r1: &c.D, bit: 4, value: true}                            // SET 4,D
+	codes[0xE3] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xE3, length: 2}, r1: &c.E, bit: 4, value: true}                            // SET 4,E
+	codes[0xE4] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xE4, length: 2}, r1: &c.H, bit: 4, value: true}                            // SET 4,H
+	codes[0xE5] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xE5, length: 2}, r1: &c.L, bit: 4, value: true}                            // SET 4,L
+	codes[0xE6] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xE6, length: 2}, r1: &c.HL, bit: 4, value: true}                           // SET 4,(HL)
+	codes[0xE7] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xE7, length: 2}, r1: &c.A, bit: 4, value: true}                            // SET 4,A
+	codes[0xE8] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xE8, length: 2}, r1: &c.B, bit: 5, value: true}                            // SET 5,B
+	codes[0xE9] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xE9, length: 2}, r1: &c.C, bit: 5, value: true}                            // SET 5,C
+	codes[0xEA] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xEA, length: 2}, r1: &c.D, bit: 5, value: true}                            // SET 5,D
+	codes[0xEB] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xEB, length: 2}, r1: &c.E, bit: 5, value: true}                            // SET 5,E
+	codes[0xEC] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xEC, length: 2}, r1: &c.H, bit: 5, value: true}                            // SET 5,H
+	codes[0xED] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xED, length: 2}, r1: &c.L, bit: 5, value: true}                            // SET 5,L
+	codes[0xEE] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xEE, length: 2}, r1: &c.HL, bit: 5, value: true}                           // SET 5,(HL)
+	codes[0xEF] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xEF, length: 2}, r1: &c.A, bit: 5, value: true}                            // SET 5,A
+	codes[0xF0] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xF0, length: 2}, r1: &c.B, bit: 6, value: true}                            // SET 6,B
+	codes[0xF1] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xF1, length: 2}, r1: &c.C, bit: 6, value: true}                            // SET 6,C
+	codes[0xF2] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xF2, length: 2}, r1: &c.D, bit: 6, value: true}                            // SET 6,D
+	codes[0xF3] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xF3, length: 2}, r1: &c.E, bit: 6, value: true}                            // SET 6,E
+	codes[0xF4] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xF4, length: 2}, r1: &c.H, bit: 6, value: true}                            // SET 6,H
+	codes[0xF5] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xF5, length: 2}, r1: &c.L, bit: 6, value: true}                            // SET 6,L
+	codes[0xF6] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xF6, length: 2}, r1: &c.HL, bit: 6, value: true}                           // SET 6,(HL)
+	codes[0xF7] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xF7, length: 2}, r1: &c.A, bit: 6, value: true}                            // SET 6,A
+	codes[0xF8] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xF8, length: 2}, r1: &c.B, bit: 7, value: true}                            // SET 7,B
+	codes[0xF9] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xF9, length: 2}, r1: &c.C, bit: 7, value: true}                            // SET 7,C
+	codes[0xFA] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xFA, length: 2}, r1: &c.D, bit: 7, value: true}                            // SET 7,D
+	codes[0xFB] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xFB, length: 2}, r1: &c.E, bit: 7, value: true}                            // SET 7,E
+	codes[0xFC] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xFC, length: 2}, r1: &c.H, bit: 7, value: true}                            // SET 7,H
+	codes[0xFD] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xFD, length: 2}, r1: &c.L, bit: 7, value: true}                            // SET 7,L
+	codes[0xFE] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xFE, length: 2}, r1: &c.HL, bit: 7, value: true}                           // SET 7,(HL)
+	codes[0xFF] = &SetBitOpCode{BaseOpCode: BaseOpCode{code: 0xFF, length: 2}, r1: &c.A, bit: 7, value: true}                            // SET 7,A
+	return codes
+}
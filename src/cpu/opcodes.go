@@ -2,6 +2,8 @@ package cpu
 
 import (
 	"fmt"
+	"strings"
+	"types"
 	"utils"
 )
 
@@ -25,16 +27,41 @@ func ModifierToString(mod Modifier) string {
 	}
 }
 
+// Conditions for jumps, calls and returns
+type Condition int
+const (
+	Always Condition = iota
+	NotZero
+	Zero
+	NoCarry
+	Carry
+)
+
+// Prefix for the opcode name, e.g. "NZ," for JP NZ,a16. Empty when unconditional
+func conditionPrefix(condition Condition) string {
+	switch condition {
+	case NotZero:
+		return "NZ,"
+	case Zero:
+		return "Z,"
+	case NoCarry:
+		return "NC,"
+	case Carry:
+		return "C,"
+	default:
+		return ""
+	}
+}
+
 type Ld8BitRegisterOpCode struct {
 	BaseOpCode
 	r1 *Register8Bit
 	r2 *Register8Bit
 }
 
-func (b *Ld8BitRegisterOpCode) Run(cpu *Cpu) (int, error) {
+func (b *Ld8BitRegisterOpCode) Run(cpu *Cpu) (int, bool, error) {
 	b.r1.Assign(b.r2.Retrieve())
-	cpu.IncrementPC(1)
-	return 4, nil
+	return 4, false, nil
 }
 
 func (b *Ld8BitRegisterOpCode) Name() string {
@@ -49,17 +76,16 @@ type LdRegIntoMemOpCode struct {
 	decrementR1 bool
 }
 
-func (b *LdRegIntoMemOpCode) Run(cpu *Cpu) (int, error) {
+func (b *LdRegIntoMemOpCode) Run(cpu *Cpu) (int, bool, error) {
 	dest := b.r1.Retrieve()
-	cpu.memory.Set(dest, b.r2.Retrieve())
+	cpu.writeByte(dest, b.r2.Retrieve())
 	if b.incrementR1 {
 		b.r1.Increment()
 	}
 	if b.decrementR1 {
 		b.r1.Decrement()
 	}
-	cpu.IncrementPC(1)
-	return 8, nil
+	return 8, false, nil
 }
 
 func (b *LdRegIntoMemOpCode) Name() string {
@@ -81,11 +107,11 @@ type LdMemIntoRegOpCode struct {
 	decrementR2 bool
 }
 
-func (b *LdMemIntoRegOpCode) Run(cpu *Cpu) (int, error) {
+func (b *LdMemIntoRegOpCode) Run(cpu *Cpu) (int, bool, error) {
 	src := b.r2.Retrieve()
-	val, err := cpu.memory.Get(src)
+	val, err := cpu.readByte(src)
 	if err != nil {
-		return -1, err
+		return -1, false, err
 	}
 
 	if b.incrementR2 {
@@ -96,8 +122,7 @@ func (b *LdMemIntoRegOpCode) Run(cpu *Cpu) (int, error) {
 	}
 
 	b.r1.Assign(val)
-	cpu.IncrementPC(1)
-	return 8, nil
+	return 8, false, nil
 }
 
 func (b *LdMemIntoRegOpCode) Name() string {
@@ -110,19 +135,41 @@ func (b *LdMemIntoRegOpCode) Name() string {
 	return fmt.Sprintf("LD %s,(%s%s)", b.r1.Name, b.r2.Name, modifier)
 }
 
+// Loads between A and memory addressed by something other than a register
+// pair: (a16), (a8) and (C)
+type LdByteSourceOpCode struct {
+	BaseOpCode
+	r1 ByteSource
+	r2 ByteSource
+	highPage bool // Prints as LDH
+}
+
+func (b *LdByteSourceOpCode) Run(cpu *Cpu) (int, bool, error) {
+	val, getCycles := b.r2.GetValue(cpu)
+	setCycles := b.r1.SetValue(cpu, val)
+	return 4 + getCycles + setCycles, false, nil
+}
+
+func (b *LdByteSourceOpCode) Name() string {
+	base := "LD"
+	if b.highPage {
+		base = "LDH"
+	}
+	return fmt.Sprintf("%s %s,%s", base, b.r1.PrintableName(), b.r2.PrintableName())
+}
+
 type Ld8BitImmediateOpCode struct {
 	BaseOpCode
 	r1 *Register8Bit
 }
 
-func (b *Ld8BitImmediateOpCode) Run(cpu *Cpu) (int, error) {
+func (b *Ld8BitImmediateOpCode) Run(cpu *Cpu) (int, bool, error) {
 	immediateByte, err := cpu.LoadImmediateByte()
 	if err != nil {
-		return -1, err
+		return -1, false, err
 	}
 	b.r1.Assign(immediateByte)
-	cpu.IncrementPC(2)
-	return 8, nil
+	return 8, false, nil
 }
 
 func (b *Ld8BitImmediateOpCode) Name() string {
@@ -134,36 +181,100 @@ type Ld16BitImmediateOpCode struct {
 	r1 *Register16Bit
 }
 
-func (b *Ld16BitImmediateOpCode) Run(cpu *Cpu) (int, error) {
+func (b *Ld16BitImmediateOpCode) Run(cpu *Cpu) (int, bool, error) {
 	immediateData, err := cpu.LoadImmediateWord()
 	if err != nil {
-		return -1, err
+		return -1, false, err
 	}
 	b.r1.Assign(immediateData)
-	cpu.IncrementPC(3)
-	return 12, nil
+	return 12, false, nil
 }
 
 func (b *Ld16BitImmediateOpCode) Name() string {
 	return fmt.Sprintf("LD %s,d16", b.r1.Name)
 }
 
+// LD SP,HL
+type Ld16BitRegisterOpCode struct {
+	BaseOpCode
+	r1 *Register16Bit
+	r2 *Register16Bit
+}
+
+func (b *Ld16BitRegisterOpCode) Run(cpu *Cpu) (int, bool, error) {
+	b.r1.Assign(b.r2.Retrieve())
+	return 8, false, nil
+}
+
+func (b *Ld16BitRegisterOpCode) Name() string {
+	return fmt.Sprintf("LD %s,%s", b.r1.Name, b.r2.Name)
+}
+
+// LD (a16),SP
+type LdSPIntoMemOpCode struct {
+	BaseOpCode
+	r1 *Register16Bit // Always SP
+}
+
+func (b *LdSPIntoMemOpCode) Run(cpu *Cpu) (int, bool, error) {
+	address, err := cpu.LoadImmediateWord()
+	if err != nil {
+		return -1, false, err
+	}
+	lsb, msb := b.r1.Retrieve().ToBytes()
+	if err := cpu.writeByte(address, lsb); err != nil {
+		return -1, false, err
+	}
+	if err := cpu.writeByte(address + 1, msb); err != nil {
+		return -1, false, err
+	}
+	return 20, false, nil
+}
+
+func (b *LdSPIntoMemOpCode) Name() string {
+	return fmt.Sprintf("LD (a16),%s", b.r1.Name)
+}
+
+// LD HL,SP+r8
+type LdSPOffsetOpCode struct {
+	BaseOpCode
+	r1 *Register16Bit // Always HL
+	r2 *Register16Bit // Always SP
+}
+
+func (b *LdSPOffsetOpCode) Run(cpu *Cpu) (int, bool, error) {
+	offset, err := cpu.LoadImmediateByte()
+	if err != nil {
+		return -1, false, err
+	}
+	result := utils.AddSigned8BitOffset(b.r2.Retrieve(), offset)
+	b.r1.Assign(result.Result)
+	cpu.SetFlag(Z, false)
+	cpu.SetFlag(N, false)
+	cpu.SetFlag(H, result.HalfCarry)
+	cpu.SetFlag(C, result.Carry)
+	return 12, false, nil
+}
+
+func (b *LdSPOffsetOpCode) Name() string {
+	return fmt.Sprintf("LD %s,%s+r8", b.r1.Name, b.r2.Name)
+}
+
 type LdMemoryImmediateOpCode struct {
 	BaseOpCode
 	r1 *Register16Bit // This is always HL?
 }
 
-func (b *LdMemoryImmediateOpCode) Run(cpu *Cpu) (int, error) {
+func (b *LdMemoryImmediateOpCode) Run(cpu *Cpu) (int, bool, error) {
 	immediateData, err := cpu.LoadImmediateByte()
 	if err != nil {
-		return -1, err
+		return -1, false, err
 	}
 	targetAddress := b.r1.Retrieve()
-	if err := cpu.memory.Set(targetAddress, immediateData); err != nil {
-		return -1, err
+	if err := cpu.writeByte(targetAddress, immediateData); err != nil {
+		return -1, false, err
 	}
-	cpu.IncrementPC(2)
-	return 12, nil
+	return 12, false, nil
 }
 
 func (b *LdMemoryImmediateOpCode) Name() string {
@@ -175,13 +286,12 @@ type Inc8BitRegOpCode struct {
 	r1 ByteSource
 }
 
-func (b *Inc8BitRegOpCode) Run(cpu *Cpu) (int, error) {
-	zero, halfCarry, cycles:= b.r1.IncrementValue(cpu.memory)
+func (b *Inc8BitRegOpCode) Run(cpu *Cpu) (int, bool, error) {
+	zero, halfCarry, cycles:= b.r1.IncrementValue(cpu)
 	cpu.SetFlag(Z, zero)
 	cpu.SetFlag(H, halfCarry)
 	cpu.SetFlag(N, false)
-	cpu.IncrementPC(1)
-	return 4 + cycles, nil
+	return 4 + cycles, false, nil
 }
 
 func (b *Inc8BitRegOpCode) Name() string {
@@ -193,21 +303,20 @@ type IncMemOpCode struct {
 	r1 *Register16Bit
 }
 
-func (b *IncMemOpCode) Run(cpu *Cpu) (int, error) {
-	val, err := cpu.memory.Get(b.r1.Retrieve())
+func (b *IncMemOpCode) Run(cpu *Cpu) (int, bool, error) {
+	val, err := cpu.readByte(b.r1.Retrieve())
 	if err != nil {
-		return -1, err
+		return -1, false, err
 	}
 	
 	incResults := utils.Add8Bit(val, 0x1)
 	cpu.SetFlag(Z, incResults.Zero)
 	cpu.SetFlag(H, incResults.HalfCarry)
 	cpu.SetFlag(N, false)
-	if err := cpu.memory.Set(b.r1.Retrieve(), incResults.Result); err != nil {
-		return -1, err
+	if err := cpu.writeByte(b.r1.Retrieve(), incResults.Result); err != nil {
+		return -1, false, err
 	}
-	cpu.IncrementPC(1)
-	return 12, nil
+	return 12, false, nil
 }
 
 func (b *IncMemOpCode) Name() string {
@@ -219,13 +328,12 @@ type Dec8BitRegOpCode struct {
 	r1 ByteSource
 }
 
-func (b *Dec8BitRegOpCode) Run(cpu *Cpu) (int, error) {
-	zero, halfCarry, cycles := b.r1.DecrementValue(cpu.memory)
+func (b *Dec8BitRegOpCode) Run(cpu *Cpu) (int, bool, error) {
+	zero, halfCarry, cycles := b.r1.DecrementValue(cpu)
 	cpu.SetFlag(Z, zero)
 	cpu.SetFlag(H, halfCarry)
 	cpu.SetFlag(N, true)
-	cpu.IncrementPC(1)
-	return 4 + cycles, nil
+	return 4 + cycles, false, nil
 }
 
 func (b *Dec8BitRegOpCode) Name() string {
@@ -239,8 +347,8 @@ type Add8BitRegOpCode struct {
 	includeCarry bool
 }
 
-func (b *Add8BitRegOpCode) Run(cpu *Cpu) (int, error) {
-	r2Val, cycles := b.r2.GetValue(cpu.memory)
+func (b *Add8BitRegOpCode) Run(cpu *Cpu) (int, bool, error) {
+	r2Val, cycles := b.r2.GetValue(cpu)
 	result := utils.Add8BitWithCarry(b.r1.Retrieve(), r2Val,
 		b.includeCarry && cpu.GetFlag(C))
 	b.r1.Assign(result.Result)
@@ -248,8 +356,7 @@ func (b *Add8BitRegOpCode) Run(cpu *Cpu) (int, error) {
 	cpu.SetFlag(H, result.HalfCarry)
 	cpu.SetFlag(C, result.Carry)
 	cpu.SetFlag(N, false)
-	cpu.IncrementPC(1)
-	return 4 + cycles, nil
+	return 4 + cycles, false, nil
 }
 
 func (b *Add8BitRegOpCode) Name() string {
@@ -266,20 +373,43 @@ type Add16BitRegOpCode struct {
 	r2 *Register16Bit
 }
 
-func (b *Add16BitRegOpCode) Run(cpu *Cpu) (int, error) {
+func (b *Add16BitRegOpCode) Run(cpu *Cpu) (int, bool, error) {
 	result := utils.Add16Bit(b.r1.Retrieve(), b.r2.Retrieve())
 	b.r1.Assign(result.Result)
 	cpu.SetFlag(H, result.HalfCarry)
 	cpu.SetFlag(C, result.Carry)
 	cpu.SetFlag(N, false)
-	cpu.IncrementPC(1)
-	return 4, nil
+	return 8, false, nil
 }
 
 func (b *Add16BitRegOpCode) Name() string {
 	return fmt.Sprintf("ADD %s,%s", b.r1.Name, b.r2.Name)
 }
 
+// ADD SP,r8
+type AddSPOffsetOpCode struct {
+	BaseOpCode
+	r1 *Register16Bit // Always SP
+}
+
+func (b *AddSPOffsetOpCode) Run(cpu *Cpu) (int, bool, error) {
+	offset, err := cpu.LoadImmediateByte()
+	if err != nil {
+		return -1, false, err
+	}
+	result := utils.AddSigned8BitOffset(b.r1.Retrieve(), offset)
+	b.r1.Assign(result.Result)
+	cpu.SetFlag(Z, false)
+	cpu.SetFlag(N, false)
+	cpu.SetFlag(H, result.HalfCarry)
+	cpu.SetFlag(C, result.Carry)
+	return 16, false, nil
+}
+
+func (b *AddSPOffsetOpCode) Name() string {
+	return fmt.Sprintf("ADD %s,r8", b.r1.Name)
+}
+
 type Sub8BitRegOpCode struct {
 	BaseOpCode
 	r1 *Register8Bit
@@ -287,8 +417,8 @@ type Sub8BitRegOpCode struct {
 	includeCarry bool
 }
 
-func (b *Sub8BitRegOpCode) Run(cpu *Cpu) (int, error) {
-	r2Val, cycles := b.r2.GetValue(cpu.memory)
+func (b *Sub8BitRegOpCode) Run(cpu *Cpu) (int, bool, error) {
+	r2Val, cycles := b.r2.GetValue(cpu)
 	result := utils.Subtract8BitWithCarry(b.r1.Retrieve(), r2Val,
 		b.includeCarry && cpu.GetFlag(C))
 	b.r1.Assign(result.Result)
@@ -296,8 +426,7 @@ func (b *Sub8BitRegOpCode) Run(cpu *Cpu) (int, error) {
 	cpu.SetFlag(H, result.HalfCarry)
 	cpu.SetFlag(C, result.Carry)
 	cpu.SetFlag(N, true)
-	cpu.IncrementPC(1)
-	return 4 + cycles, nil
+	return 4 + cycles, false, nil
 }
 
 func (b *Sub8BitRegOpCode) Name() string {
@@ -314,18 +443,17 @@ type IncDec16Bit struct {
 	mod Modifier
 }
 
-func (b *IncDec16Bit) Run(cpu *Cpu) (int, error) {
+func (b *IncDec16Bit) Run(cpu *Cpu) (int, bool, error) {
 	switch b.mod {
 	case Increment:
 		b.target.Increment()
 	case Decrement:
 		b.target.Decrement()
 	default:
-		return -1, fmt.Errorf("Bad modifier: %d", b.mod)
+		return -1, false, fmt.Errorf("Bad modifier: %d", b.mod)
 	}
 
-	cpu.IncrementPC(1)
-	return 8, nil
+	return 8, false, nil
 }
 
 func (b *IncDec16Bit) Name() string {
@@ -340,14 +468,70 @@ type NoOpCode struct {
 	BaseOpCode
 }
 
-func (b *NoOpCode) Run(cpu *Cpu) (int, error) {
-	return 4, nil
+func (b *NoOpCode) Run(cpu *Cpu) (int, bool, error) {
+	return 4, false, nil
 }
 
 func (b *NoOpCode) Name() string {
 	return "NOP"
 }
 
+// DAA: turns A back into binary coded decimal after an add or subtract
+type DecimalAdjustOpCode struct {
+	BaseOpCode
+	r1 *Register8Bit // Always A
+}
+
+func (b *DecimalAdjustOpCode) Run(cpu *Cpu) (int, bool, error) {
+	result := utils.DecimalAdjust(b.r1.Retrieve(), cpu.GetFlag(N), cpu.GetFlag(H), cpu.GetFlag(C))
+	b.r1.Assign(result.Result)
+	cpu.SetFlag(Z, result.Zero)
+	cpu.SetFlag(H, result.HalfCarry)
+	cpu.SetFlag(C, result.Carry)
+	return 4, false, nil
+}
+
+func (b *DecimalAdjustOpCode) Name() string {
+	return "DAA"
+}
+
+// CPL: flips every bit of A
+type ComplementOpCode struct {
+	BaseOpCode
+	r1 *Register8Bit // Always A
+}
+
+func (b *ComplementOpCode) Run(cpu *Cpu) (int, bool, error) {
+	b.r1.Assign(^b.r1.Retrieve())
+	cpu.SetFlag(N, true)
+	cpu.SetFlag(H, true)
+	return 4, false, nil
+}
+
+func (b *ComplementOpCode) Name() string {
+	return "CPL"
+}
+
+// SCF sets the carry flag, CCF flips it
+type CarryFlagOpCode struct {
+	BaseOpCode
+	complement bool
+}
+
+func (b *CarryFlagOpCode) Run(cpu *Cpu) (int, bool, error) {
+	cpu.SetFlag(C, !b.complement || !cpu.GetFlag(C))
+	cpu.SetFlag(N, false)
+	cpu.SetFlag(H, false)
+	return 4, false, nil
+}
+
+func (b *CarryFlagOpCode) Name() string {
+	if b.complement {
+		return "CCF"
+	}
+	return "SCF"
+}
+
 // AND, XOR, OR, CP
 
 type LogicalOp int
@@ -365,9 +549,9 @@ type Logical8BitOp struct {
 	operation LogicalOp
 }
 
-func (b *Logical8BitOp) Run(cpu *Cpu) (int, error) {
+func (b *Logical8BitOp) Run(cpu *Cpu) (int, bool, error) {
 	targetVal := b.target.Retrieve()
-	sourceVal, sourceCycles := b.source.GetValue(cpu.memory)
+	sourceVal, sourceCycles := b.source.GetValue(cpu)
 	switch b.operation {
 	case AND:
 		andedVal := targetVal & sourceVal
@@ -389,6 +573,7 @@ func (b *Logical8BitOp) Run(cpu *Cpu) (int, error) {
 		cpu.SetFlag(N, false)
 		cpu.SetFlag(H, false)
 		cpu.SetFlag(C, false)
+		b.target.Assign(orVal)
 	case CP:
 		subtractResults := utils.Subtract8Bit(targetVal, sourceVal)
 		cpu.SetFlag(Z, subtractResults.Result == 0)
@@ -396,10 +581,9 @@ func (b *Logical8BitOp) Run(cpu *Cpu) (int, error) {
 		cpu.SetFlag(H, subtractResults.HalfCarry)
 		cpu.SetFlag(C, subtractResults.Carry)
 	default:
-		return -1, fmt.Errorf("Unknown operation: %d", b.operation)
+		return -1, false, fmt.Errorf("Unknown operation: %d", b.operation)
 	}
-	cpu.IncrementPC(1)
-	return 4 + sourceCycles, nil
+	return 4 + sourceCycles, false, nil
 }
 
 func (b *Logical8BitOp) Name() string {
@@ -413,7 +597,7 @@ func (b *Logical8BitOp) Name() string {
 	case CP:
 		return fmt.Sprintf("CP %s", b.source.PrintableName())
 	default:
-		return fmt.Sprintf("Unknown operation: %s", b.operation)
+		return fmt.Sprintf("Unknown operation: %d", b.operation)
 	}
 }
 
@@ -427,13 +611,14 @@ type RotateOpCode struct {
 	BaseOpCode
 	r1 ByteSource
 	direction Direction
-	includeCarry bool
+	// RLC/RRC feed the bit rotated out straight back in, RL/RR rotate through the carry flag
+	circular bool
 	isCB bool
 }
 
-func (b *RotateOpCode) Run(cpu *Cpu) (int, error) {
+func (b *RotateOpCode) Run(cpu *Cpu) (int, bool, error) {
 	var bitVal bool
-	sourceValue, cycles := b.r1.GetValue(cpu.memory)
+	sourceValue, cycles := b.r1.GetValue(cpu)
 
 	var calculation byte
 	if b.direction == Left {
@@ -444,24 +629,30 @@ func (b *RotateOpCode) Run(cpu *Cpu) (int, error) {
 		calculation = sourceValue >> 1
 	}
 
-	if b.includeCarry && cpu.GetFlag(C) {
+	carryIn := cpu.GetFlag(C)
+	if b.circular {
+		carryIn = bitVal
+	}
+	if carryIn {
 		if b.direction == Left {
-			calculation ^= 0x80
+			calculation |= 0x01
 		} else {
-			calculation ^= 0x01
+			calculation |= 0x80
 		}
 	}
 
 	cpu.SetFlag(C, bitVal)
-	cycles += b.r1.SetValue(cpu.memory, calculation)
-	
-	if !b.isCB {
-		cpu.SetFlag(Z, false)
-	}
+	cycles += b.r1.SetValue(cpu, calculation)
+
+	// Only the CB versions set Z, RLCA and friends always clear it
+	cpu.SetFlag(Z, b.isCB && calculation == 0)
 	cpu.SetFlag(H, false)
 	cpu.SetFlag(N, false)
 
-	return 4 + cycles, nil
+	if b.isCB {
+		return 8 + cycles, false, nil
+	}
+	return 4 + cycles, false, nil
 }
 
 func (b *RotateOpCode) Name() string {
@@ -471,7 +662,7 @@ func (b *RotateOpCode) Name() string {
 	}
 	name := "R" + dir
 
-	if b.includeCarry {
+	if b.circular {
 		name += "C"
 	}
 	
@@ -482,3 +673,392 @@ func (b *RotateOpCode) Name() string {
 	name += b.r1.PrintableName()
 	return name
 }
+
+// SLA, SRA and SRL. All CB prefixed
+type ShiftOpCode struct {
+	BaseOpCode
+	r1 ByteSource
+	direction Direction
+	// SRA keeps bit 7 in place, SRL shifts in a zero
+	arithmetic bool
+}
+
+func (b *ShiftOpCode) Run(cpu *Cpu) (int, bool, error) {
+	var bitVal bool
+	sourceValue, cycles := b.r1.GetValue(cpu)
+
+	var calculation byte
+	if b.direction == Left {
+		bitVal = 0x80 & sourceValue == 0x80
+		calculation = sourceValue << 1
+	} else {
+		bitVal = 0x01 & sourceValue == 0x01
+		calculation = sourceValue >> 1
+		if b.arithmetic {
+			calculation |= sourceValue & 0x80
+		}
+	}
+
+	cycles += b.r1.SetValue(cpu, calculation)
+	cpu.SetFlag(Z, calculation == 0)
+	cpu.SetFlag(N, false)
+	cpu.SetFlag(H, false)
+	cpu.SetFlag(C, bitVal)
+	return 8 + cycles, false, nil
+}
+
+func (b *ShiftOpCode) Name() string {
+	if b.direction == Left {
+		return fmt.Sprintf("SLA %s", b.r1.PrintableName())
+	}
+	if b.arithmetic {
+		return fmt.Sprintf("SRA %s", b.r1.PrintableName())
+	}
+	return fmt.Sprintf("SRL %s", b.r1.PrintableName())
+}
+
+// Swaps the upper and lower nibbles
+type SwapOpCode struct {
+	BaseOpCode
+	r1 ByteSource
+}
+
+func (b *SwapOpCode) Run(cpu *Cpu) (int, bool, error) {
+	sourceValue, cycles := b.r1.GetValue(cpu)
+	calculation := (sourceValue << 4) | (sourceValue >> 4)
+	cycles += b.r1.SetValue(cpu, calculation)
+	cpu.SetFlag(Z, calculation == 0)
+	cpu.SetFlag(N, false)
+	cpu.SetFlag(H, false)
+	cpu.SetFlag(C, false)
+	return 8 + cycles, false, nil
+}
+
+func (b *SwapOpCode) Name() string {
+	return fmt.Sprintf("SWAP %s", b.r1.PrintableName())
+}
+
+// BIT b,n: sets Z if the bit is clear
+type TestBitOpCode struct {
+	BaseOpCode
+	r1 ByteSource
+	bit uint
+}
+
+func (b *TestBitOpCode) Run(cpu *Cpu) (int, bool, error) {
+	sourceValue, cycles := b.r1.GetValue(cpu)
+	cpu.SetFlag(Z, sourceValue & (1 << b.bit) == 0)
+	cpu.SetFlag(N, false)
+	cpu.SetFlag(H, true)
+	// (HL) only needs the read, so it comes out at 12 rather than 16
+	return 8 + cycles, false, nil
+}
+
+func (b *TestBitOpCode) Name() string {
+	return fmt.Sprintf("BIT %d,%s", b.bit, b.r1.PrintableName())
+}
+
+// RES b,n and SET b,n. Neither touches the flags
+type SetBitOpCode struct {
+	BaseOpCode
+	r1 ByteSource
+	bit uint
+	value bool
+}
+
+func (b *SetBitOpCode) Run(cpu *Cpu) (int, bool, error) {
+	sourceValue, cycles := b.r1.GetValue(cpu)
+	if b.value {
+		sourceValue |= 1 << b.bit
+	} else {
+		sourceValue &= ^(1 << b.bit)
+	}
+	cycles += b.r1.SetValue(cpu, sourceValue)
+	return 8 + cycles, false, nil
+}
+
+func (b *SetBitOpCode) Name() string {
+	base := "RES"
+	if b.value {
+		base = "SET"
+	}
+	return fmt.Sprintf("%s %d,%s", base, b.bit, b.r1.PrintableName())
+}
+
+// 0xCB: looks up the following byte in the CB table and runs it
+type CBPrefixOpCode struct {
+	BaseOpCode
+}
+
+func (b *CBPrefixOpCode) Run(cpu *Cpu) (int, bool, error) {
+	code, err := cpu.LoadImmediateByte()
+	if err != nil {
+		return -1, false, err
+	}
+	op, exists := cpu.cbCodes[code]
+	if !exists {
+		return -1, false, &UnknownOpCodeError{
+			PC: cpu.PC.Retrieve() + 1,
+			Code: code,
+		}
+	}
+	// CB opcodes include the cost of fetching the prefix
+	return op.Run(cpu)
+}
+
+func (b *CBPrefixOpCode) Name() string {
+	return "PREFIX CB"
+}
+
+type PushOpCode struct {
+	BaseOpCode
+	r1 *Register16Bit
+}
+
+func (b *PushOpCode) Run(cpu *Cpu) (int, bool, error) {
+	cpu.internalCycle()
+	if err := cpu.PushWord(b.r1.Retrieve()); err != nil {
+		return -1, false, err
+	}
+	return 16, false, nil
+}
+
+func (b *PushOpCode) Name() string {
+	return fmt.Sprintf("PUSH %s", b.r1.Name)
+}
+
+type PopOpCode struct {
+	BaseOpCode
+	r1 *Register16Bit
+}
+
+func (b *PopOpCode) Run(cpu *Cpu) (int, bool, error) {
+	val, err := cpu.PopWord()
+	if err != nil {
+		return -1, false, err
+	}
+	// POP AF can't set the low nibble of F, which the register drops itself
+	b.r1.Assign(val)
+	return 12, false, nil
+}
+
+func (b *PopOpCode) Name() string {
+	return fmt.Sprintf("POP %s", b.r1.Name)
+}
+
+// JP a16 and JP cc,a16
+type JumpOpCode struct {
+	BaseOpCode
+	condition Condition
+}
+
+func (b *JumpOpCode) Run(cpu *Cpu) (int, bool, error) {
+	target, err := cpu.LoadImmediateWord()
+	if err != nil {
+		return -1, false, err
+	}
+	if !cpu.ConditionMet(b.condition) {
+		return 12, false, nil
+	}
+	cpu.PC.Assign(target)
+	return 16, true, nil
+}
+
+func (b *JumpOpCode) Name() string {
+	return fmt.Sprintf("JP %sa16", conditionPrefix(b.condition))
+}
+
+// JP (HL). Despite the name this jumps to HL itself rather than reading memory
+type JumpRegisterOpCode struct {
+	BaseOpCode
+	r1 *Register16Bit
+}
+
+func (b *JumpRegisterOpCode) Run(cpu *Cpu) (int, bool, error) {
+	cpu.PC.Assign(b.r1.Retrieve())
+	return 4, true, nil
+}
+
+func (b *JumpRegisterOpCode) Name() string {
+	return fmt.Sprintf("JP (%s)", b.r1.Name)
+}
+
+// JR r8 and JR cc,r8. The offset is signed and relative to the next instruction
+type JumpRelativeOpCode struct {
+	BaseOpCode
+	condition Condition
+}
+
+func (b *JumpRelativeOpCode) Run(cpu *Cpu) (int, bool, error) {
+	offset, err := cpu.LoadImmediateByte()
+	if err != nil {
+		return -1, false, err
+	}
+	if !cpu.ConditionMet(b.condition) {
+		return 8, false, nil
+	}
+	target := cpu.PC.Retrieve() + types.Word(b.Length()) + types.Word(int8(offset))
+	cpu.PC.Assign(target)
+	return 12, true, nil
+}
+
+func (b *JumpRelativeOpCode) Name() string {
+	return fmt.Sprintf("JR %sr8", conditionPrefix(b.condition))
+}
+
+// CALL a16 and CALL cc,a16
+type CallOpCode struct {
+	BaseOpCode
+	condition Condition
+}
+
+func (b *CallOpCode) Run(cpu *Cpu) (int, bool, error) {
+	target, err := cpu.LoadImmediateWord()
+	if err != nil {
+		return -1, false, err
+	}
+	if !cpu.ConditionMet(b.condition) {
+		return 12, false, nil
+	}
+	returnAddress := cpu.PC.Retrieve() + types.Word(b.Length())
+	cpu.internalCycle()
+	if err := cpu.PushWord(returnAddress); err != nil {
+		return -1, false, err
+	}
+	cpu.PC.Assign(target)
+	return 24, true, nil
+}
+
+func (b *CallOpCode) Name() string {
+	return fmt.Sprintf("CALL %sa16", conditionPrefix(b.condition))
+}
+
+// RET, RET cc and RETI
+type ReturnOpCode struct {
+	BaseOpCode
+	condition Condition
+	enableInterrupts bool
+}
+
+func (b *ReturnOpCode) Run(cpu *Cpu) (int, bool, error) {
+	if b.condition != Always {
+		// Checking the condition costs an extra cycle
+		cpu.internalCycle()
+	}
+	if !cpu.ConditionMet(b.condition) {
+		return 8, false, nil
+	}
+	target, err := cpu.PopWord()
+	if err != nil {
+		return -1, false, err
+	}
+	if b.enableInterrupts {
+		// Unlike EI this takes effect immediately
+		cpu.SetInterruptMasterEnable(true)
+	}
+	cpu.PC.Assign(target)
+	if b.condition != Always {
+		return 20, true, nil
+	}
+	return 16, true, nil
+}
+
+func (b *ReturnOpCode) Name() string {
+	if b.enableInterrupts {
+		return "RETI"
+	}
+	if b.condition == Always {
+		return "RET"
+	}
+	return fmt.Sprintf("RET %s", strings.TrimSuffix(conditionPrefix(b.condition), ","))
+}
+
+// RST n: calls one of the fixed vectors at the bottom of memory
+type RestartOpCode struct {
+	BaseOpCode
+	target types.Word
+}
+
+func (b *RestartOpCode) Run(cpu *Cpu) (int, bool, error) {
+	returnAddress := cpu.PC.Retrieve() + types.Word(b.Length())
+	cpu.internalCycle()
+	if err := cpu.PushWord(returnAddress); err != nil {
+		return -1, false, err
+	}
+	cpu.PC.Assign(b.target)
+	return 16, true, nil
+}
+
+func (b *RestartOpCode) Name() string {
+	return fmt.Sprintf("RST %02XH", uint16(b.target))
+}
+
+// DI and EI
+type InterruptMasterOpCode struct {
+	BaseOpCode
+	enable bool
+}
+
+func (b *InterruptMasterOpCode) Run(cpu *Cpu) (int, bool, error) {
+	if b.enable {
+		// IME is set once the next instruction finishes, see Cpu.Step
+		if !cpu.ime {
+			cpu.imePending = true
+		}
+	} else {
+		cpu.SetInterruptMasterEnable(false)
+	}
+	return 4, false, nil
+}
+
+func (b *InterruptMasterOpCode) Name() string {
+	if b.enable {
+		return "EI"
+	}
+	return "DI"
+}
+
+type HaltOpCode struct {
+	BaseOpCode
+}
+
+func (b *HaltOpCode) Run(cpu *Cpu) (int, bool, error) {
+	if !cpu.ime && cpu.pendingInterrupts() != 0 {
+		// DMG bug: HALT exits immediately and the next byte is read twice
+		cpu.haltBug = true
+	} else {
+		cpu.halted = true
+	}
+	return 4, false, nil
+}
+
+func (b *HaltOpCode) Name() string {
+	return "HALT"
+}
+
+type StopOpCode struct {
+	BaseOpCode
+}
+
+func (b *StopOpCode) Run(cpu *Cpu) (int, bool, error) {
+	cpu.stopped = true
+	return 4, false, nil
+}
+
+func (b *StopOpCode) Name() string {
+	return "STOP 0"
+}
+
+// One of the eleven unused opcodes. See IllegalOpCodePolicy for what happens
+// when one is run
+type IllegalOpCode struct {
+	BaseOpCode
+}
+
+func (b *IllegalOpCode) Run(cpu *Cpu) (int, bool, error) {
+	return cpu.handleIllegalOpCode(b.code)
+}
+
+func (b *IllegalOpCode) Name() string {
+	return "ILLEGAL"
+}
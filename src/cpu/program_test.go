@@ -0,0 +1,68 @@
+package cpu_test
+
+import (
+	"asm"
+	"cpu"
+	"memory"
+	"testing"
+)
+
+// Runs an assembled program from $0100 until it halts
+func runProgram(t *testing.T, source string) (*cpu.Cpu, *memory.Memory) {
+	mem := memory.SetupBlankMemory(0x10000)
+	program, err := asm.Assemble(source)
+	if err != nil {
+		t.Fatalf("Error assembling program: %v", err)
+	}
+	if err := program.Load(mem); err != nil {
+		t.Fatalf("Error loading program: %v", err)
+	}
+	c := cpu.NewCpu(mem)
+	c.PC.Assign(0x100)
+	c.SP.Assign(0xFFFE)
+	for steps := 0; !c.Halted(); steps++ {
+		if steps > 10000 {
+			t.Fatalf("Program didn't halt, PC at %s", c.PC.Retrieve())
+		}
+		if _, err := c.Step(); err != nil {
+			t.Fatalf("Error at %s: %v", c.PC.Retrieve(), err)
+		}
+	}
+	return c, mem
+}
+
+func TestProgram_loopAndCall(t *testing.T) {
+	c, mem := runProgram(t, `
+SECTION "Main", ROM0[$0100]
+Main:
+	ld b, 10
+	xor a
+.loop:
+	add a, b
+	dec b
+	jr nz, .loop
+	call Store
+	halt
+
+Store:
+	ld [wTotal], a
+	cpl
+	ld [wTotal + 1], a
+	ret
+
+SECTION "Variables", WRAM0
+wTotal: ds 2
+`)
+	if a := c.A.Retrieve(); a != ^byte(55) {
+		t.Errorf("Expected A to be 0x%02X, got 0x%02X", ^byte(55), a)
+	}
+	if total, _ := mem.Get(0xC000); total != 55 {
+		t.Errorf("Expected 55 stored at 0xC000, got %d", total)
+	}
+	if complement, _ := mem.Get(0xC001); complement != ^byte(55) {
+		t.Errorf("Expected 0x%02X stored at 0xC001, got 0x%02X", ^byte(55), complement)
+	}
+	if sp := c.SP.Retrieve(); sp != 0xFFFE {
+		t.Errorf("Expected the stack to be balanced, SP at %s", sp)
+	}
+}
@@ -0,0 +1,92 @@
+package cpu
+
+import (
+	"fmt"
+	"strings"
+	"types"
+)
+
+// Snapshot of the CPU's programmer visible state
+type Registers struct {
+	A, F, B, C, D, E, H, L byte
+	SP, PC types.Word
+	// Interrupt master enable
+	IME bool
+	Halted bool
+}
+
+func (c *Cpu) Registers() Registers {
+	return Registers{
+		A: c.A.Retrieve(),
+		F: c.F.Retrieve(),
+		B: c.B.Retrieve(),
+		C: c.C.Retrieve(),
+		D: c.D.Retrieve(),
+		E: c.E.Retrieve(),
+		H: c.H.Retrieve(),
+		L: c.L.Retrieve(),
+		SP: c.SP.Retrieve(),
+		PC: c.PC.Retrieve(),
+		IME: c.ime,
+		Halted: c.halted,
+	}
+}
+
+// Restores a snapshot. Any EI waiting to take effect is dropped, and the
+// low nibble of F is cleared as it always reads 0 on the hardware
+func (c *Cpu) SetRegisters(r Registers) {
+	c.A.Assign(r.A)
+	c.F.Assign(r.F & 0xF0)
+	c.B.Assign(r.B)
+	c.C.Assign(r.C)
+	c.D.Assign(r.D)
+	c.E.Assign(r.E)
+	c.H.Assign(r.H)
+	c.L.Assign(r.L)
+	c.SP.Assign(r.SP)
+	c.PC.Assign(r.PC)
+	c.ime = r.IME
+	c.imePending = false
+	c.halted = r.Halted
+}
+
+func (r Registers) Equal(other Registers) bool {
+	return r == other
+}
+
+func (r Registers) String() string {
+	return fmt.Sprintf("A:%02X F:%02X B:%02X C:%02X D:%02X E:%02X H:%02X L:%02X SP:%04X PC:%04X IME:%t HALT:%t",
+		r.A, r.F, r.B, r.C, r.D, r.E, r.H, r.L, uint16(r.SP), uint16(r.PC), r.IME, r.Halted)
+}
+
+// Lists the registers that differ from other, e.g. "B: 0x12 -> 0x34" where
+// other holds 0x34. Empty when they're equal
+func (r Registers) Diff(other Registers) string {
+	var diffs []string
+	diff8 := func(name string, before, after byte) {
+		if before != after {
+			diffs = append(diffs, fmt.Sprintf("%s: 0x%02X -> 0x%02X", name, before, after))
+		}
+	}
+	diff8("A", r.A, other.A)
+	diff8("F", r.F, other.F)
+	diff8("B", r.B, other.B)
+	diff8("C", r.C, other.C)
+	diff8("D", r.D, other.D)
+	diff8("E", r.E, other.E)
+	diff8("H", r.H, other.H)
+	diff8("L", r.L, other.L)
+	if r.SP != other.SP {
+		diffs = append(diffs, fmt.Sprintf("SP: 0x%04X -> 0x%04X", uint16(r.SP), uint16(other.SP)))
+	}
+	if r.PC != other.PC {
+		diffs = append(diffs, fmt.Sprintf("PC: 0x%04X -> 0x%04X", uint16(r.PC), uint16(other.PC)))
+	}
+	if r.IME != other.IME {
+		diffs = append(diffs, fmt.Sprintf("IME: %t -> %t", r.IME, other.IME))
+	}
+	if r.Halted != other.Halted {
+		diffs = append(diffs, fmt.Sprintf("Halted: %t -> %t", r.Halted, other.Halted))
+	}
+	return strings.Join(diffs, ", ")
+}
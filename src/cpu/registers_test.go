@@ -0,0 +1,104 @@
+package cpu
+
+import (
+	"testing"
+	"types"
+)
+
+func TestRegisters(t *testing.T) {
+	cpu, _ := setupCpu()
+	want := Registers{
+		A: 0x01, F: 0xB0, B: 0x02, C: 0x03, D: 0x04, E: 0x05, H: 0x06, L: 0x07,
+		SP: 0xFFFE, PC: 0x0100, IME: true, Halted: true,
+	}
+	cpu.SetRegisters(want)
+	if got := cpu.Registers(); !got.Equal(want) {
+		t.Errorf("Registers didn't round trip, %s", want.Diff(got))
+	}
+	if !cpu.InterruptMasterEnable() || !cpu.Halted() {
+		t.Errorf("Expected IME and HALT to be set on the CPU")
+	}
+
+	// The low nibble of F doesn't exist
+	want.F = 0xFF
+	cpu.SetRegisters(want)
+	if f := cpu.Registers().F; f != 0xF0 {
+		t.Errorf("Expected F to be masked to 0xF0, got 0x%02X", f)
+	}
+}
+
+func TestRegisters_diff(t *testing.T) {
+	a := Registers{B: 0x12, SP: 0xFFFE}
+	if diff := a.Diff(a); diff != "" {
+		t.Errorf("Expected no diff, got %q", diff)
+	}
+	b := a
+	b.B = 0x34
+	b.PC = 0x150
+	b.IME = true
+	want := "B: 0x12 -> 0x34, PC: 0x0000 -> 0x0150, IME: false -> true"
+	if diff := a.Diff(b); diff != want {
+		t.Errorf("Want diff %q, got %q", want, diff)
+	}
+	if a.Equal(b) {
+		t.Errorf("Expected %s and %s to differ", a, b)
+	}
+}
+
+// Every value through every pair and back through its halves, and the
+// other way round
+func TestRegisterPairs(t *testing.T) {
+	cpu, _ := setupCpu()
+	pairs := []struct {
+		pair *Register16Bit
+		high, low *Register8Bit
+		name string
+		// Bits of the low register that always read zero
+		lowMask byte
+	}{
+		{&cpu.AF, &cpu.A, &cpu.F, "AF", 0xF0},
+		{&cpu.BC, &cpu.B, &cpu.C, "BC", 0xFF},
+		{&cpu.DE, &cpu.D, &cpu.E, "DE", 0xFF},
+		{&cpu.HL, &cpu.H, &cpu.L, "HL", 0xFF},
+	}
+	for _, p := range pairs {
+		if p.pair.Name != p.name || p.pair.PrintableName() != "(" + p.name + ")" {
+			t.Errorf("Expected pair %s, got %s printed as %s", p.name, p.pair.Name, p.pair.PrintableName())
+		}
+		for value := 0; value <= 0xFFFF; value++ {
+			high, low := byte(value >> 8), byte(value)
+			want := types.Word(uint16(high) << 8 | uint16(low & p.lowMask))
+
+			p.pair.Assign(types.Word(value))
+			if p.high.Retrieve() != high || p.low.Retrieve() != low & p.lowMask || p.pair.Retrieve() != want {
+				t.Fatalf("%s.Assign(0x%04X) gave %s=0x%02X %s=0x%02X %s=0x%04X", p.name, value,
+					p.high.Name, p.high.Retrieve(), p.low.Name, p.low.Retrieve(), p.name, uint16(p.pair.Retrieve()))
+			}
+
+			p.pair.Assign(0)
+			p.high.Assign(high)
+			p.low.Assign(low)
+			if p.pair.Retrieve() != want {
+				t.Fatalf("%s=0x%02X %s=0x%02X read back as %s=0x%04X", p.high.Name, high, p.low.Name, low,
+					p.name, uint16(p.pair.Retrieve()))
+			}
+		}
+
+		// Halves are independent of the other pairs
+		p.pair.Assign(0xFFFF)
+		for _, other := range pairs {
+			if other.pair != p.pair && other.pair.Retrieve() == 0xFFFF & types.Word(0xFF00 | uint16(other.lowMask)) {
+				t.Errorf("Assigning %s changed %s", p.name, other.name)
+			}
+		}
+		p.pair.Assign(0)
+	}
+
+	// Flag operations can't set the missing bits either
+	cpu.F.Assign(0xFF)
+	cpu.F.SetBit(0, true)
+	cpu.F.Increment()
+	if f := cpu.F.Retrieve(); f & 0x0F != 0 {
+		t.Errorf("Expected the low nibble of F to read zero, got 0x%02X", f)
+	}
+}
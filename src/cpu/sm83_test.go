@@ -0,0 +1,251 @@
+package cpu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"memory"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"types"
+)
+
+// Runs the single step tests from https://github.com/SingleStepTests/sm83,
+// one JSON file per opcode. Point SM83_TEST_DIR at the v1 directory, or put
+// the files in test/data/sm83. Skipped when neither exists.
+const defaultSM83TestDir = "../../test/data/sm83"
+
+// A few cases in the same format, always run
+const sm83FixtureDir = "testdata/sm83"
+
+// The tests model the fetch of the next opcode overlapping execution, so PC
+// is always one past the instruction being run
+type sm83State struct {
+	PC types.Word `json:"pc"`
+	SP types.Word `json:"sp"`
+	A byte `json:"a"`
+	B byte `json:"b"`
+	C byte `json:"c"`
+	D byte `json:"d"`
+	E byte `json:"e"`
+	F byte `json:"f"`
+	H byte `json:"h"`
+	L byte `json:"l"`
+	IME byte `json:"ime"`
+	IE byte `json:"ie"`
+	RAM [][2]int `json:"ram"`
+}
+
+// Bus activity during one M-cycle
+type sm83Cycle struct {
+	Idle bool
+	Write bool
+	Address types.Word
+	Value byte
+}
+
+type sm83Case struct {
+	Name string `json:"name"`
+	Initial sm83State `json:"initial"`
+	Final sm83State `json:"final"`
+	Cycles []sm83Cycle `json:"cycles"`
+}
+
+// Cycles are [address, value, pins] where pins is "r-m" for a read, "-wm" for
+// a write, and anything else when the bus is idle. Idle cycles can also be
+// null
+func (c *sm83Cycle) UnmarshalJSON(data []byte) error {
+	var entry []interface{}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return err
+	}
+	if entry == nil {
+		*c = sm83Cycle{Idle: true}
+		return nil
+	}
+	if len(entry) != 3 {
+		return fmt.Errorf("Cycle should have 3 entries, got %s", data)
+	}
+	pins, _ := entry[2].(string)
+	address, hasAddress := entry[0].(float64)
+	value, hasValue := entry[1].(float64)
+	read, write := strings.HasPrefix(pins, "r"), strings.Contains(pins, "w")
+	if !read && !write {
+		*c = sm83Cycle{Idle: true}
+		return nil
+	}
+	if !hasAddress || !hasValue {
+		return fmt.Errorf("Cycle %s accesses memory without an address and value", data)
+	}
+	*c = sm83Cycle{Write: write, Address: types.Word(address), Value: byte(value)}
+	return nil
+}
+
+func (c sm83Cycle) String() string {
+	switch {
+	case c.Idle:
+		return "idle"
+	case c.Write:
+		return fmt.Sprintf("write %02X to %04X", c.Value, uint16(c.Address))
+	}
+	return fmt.Sprintf("read %02X from %04X", c.Value, uint16(c.Address))
+}
+
+func (s sm83State) registers() Registers {
+	return Registers{
+		A: s.A, F: s.F, B: s.B, C: s.C, D: s.D, E: s.E, H: s.H, L: s.L,
+		SP: s.SP, PC: s.PC - 1, IME: s.IME != 0,
+	}
+}
+
+func sm83TestDir() string {
+	if dir := os.Getenv("SM83_TEST_DIR"); dir != "" {
+		return dir
+	}
+	return defaultSM83TestDir
+}
+
+// A fresh CPU in a case's initial state. It runs cycle accurately, recording
+// each M-cycle's bus activity into cycles
+func loadSM83State(mem *memory.Memory, state sm83State, cycles *[]sm83Cycle) *Cpu {
+	for _, entry := range state.RAM {
+		mem.Set(types.Word(entry[0]), byte(entry[1]))
+	}
+	cpu := NewCpu(mem)
+	cpu.SetRegisters(state.registers())
+	cpu.SetInterruptEnable(state.IE)
+	cpu.SetCycleAccurate(true)
+	cpu.SetTicker(func(ticked int) {
+		for ; ticked > 0; ticked -= cyclesPerAccess {
+			*cycles = append(*cycles, sm83Cycle{Idle: true})
+		}
+	})
+	// Accesses come straight after the tick for their M-cycle
+	cpu.AddObserver(func(access Access) {
+		(*cycles)[len(*cycles) - 1] = sm83Cycle{
+			Write: access.Kind == WriteAccess,
+			Address: access.Address,
+			Value: access.Value,
+		}
+	})
+	return cpu
+}
+
+// Differences between the recorded bus activity and a case's. The case's
+// cycles start after the opcode fetch and end with the fetch of the next
+// opcode, ours start with the fetch and stop short of the next one
+func compareSM83Cycles(got []sm83Cycle, want []sm83Cycle, finalPC types.Word) []string {
+	if len(got) != len(want) {
+		return []string{fmt.Sprintf("cycles: want %d, got %d", 4 * len(want), 4 * len(got))}
+	}
+	var diffs []string
+	for i := 0; i + 1 < len(got); i++ {
+		if got[i + 1] != want[i] {
+			diffs = append(diffs, fmt.Sprintf("M-cycle %d: want %s, got %s", i + 1, want[i], got[i + 1]))
+		}
+	}
+	if next := want[len(want) - 1]; next.Idle || next.Write || next.Address != finalPC {
+		diffs = append(diffs, fmt.Sprintf("last M-cycle: want the next opcode read from %04X, case has %s", uint16(finalPC), next))
+	}
+	return diffs
+}
+
+// Differences between the CPU and a case's final state
+func compareSM83State(cpu *Cpu, mem *memory.Memory, state sm83State) []string {
+	var diffs []string
+	check8 := func(name string, got, want byte) {
+		if got != want {
+			diffs = append(diffs, fmt.Sprintf("%s: want %02X, got %02X", name, want, got))
+		}
+	}
+	got := cpu.Registers()
+	want := state.registers()
+	want.Halted = got.Halted
+	if !want.Equal(got) {
+		diffs = append(diffs, want.Diff(got))
+	}
+	check8("IE", cpu.InterruptEnable(), state.IE)
+	for _, entry := range state.RAM {
+		got, _ := mem.Get(types.Word(entry[0]))
+		check8(types.Word(entry[0]).String(), got, byte(entry[1]))
+	}
+	return diffs
+}
+
+// Runs each file as a subtest, returning a line per file for the summary
+func runSM83Files(t *testing.T, files []string) (summary []string, totalPassed int, totalCases int) {
+	mem := memory.SetupBlankMemory(0x10000)
+	for _, file := range files {
+		opcode := strings.TrimSuffix(filepath.Base(file), ".json")
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Error reading %s: %v", file, err)
+		}
+		var cases []sm83Case
+		if err := json.Unmarshal(data, &cases); err != nil {
+			t.Fatalf("Error parsing %s: %v", file, err)
+		}
+
+		passed := 0
+		t.Run(opcode, func(t *testing.T) {
+			var firstFailure string
+			for _, tc := range cases {
+				var recorded []sm83Cycle
+				cpu := loadSM83State(mem, tc.Initial, &recorded)
+				_, err := cpu.Step()
+				diffs := compareSM83State(cpu, mem, tc.Final)
+				if err != nil {
+					diffs = append(diffs, fmt.Sprintf("error: %v", err))
+				} else {
+					diffs = append(diffs, compareSM83Cycles(recorded, tc.Cycles, cpu.PC.Retrieve())...)
+				}
+				if len(diffs) == 0 {
+					passed++
+				} else if firstFailure == "" {
+					firstFailure = fmt.Sprintf("%s: %s", tc.Name, strings.Join(diffs, ", "))
+				}
+				// Clear RAM for the next case
+				for _, entry := range tc.Initial.RAM {
+					mem.Set(types.Word(entry[0]), 0)
+				}
+				for _, entry := range tc.Final.RAM {
+					mem.Set(types.Word(entry[0]), 0)
+				}
+			}
+			if passed != len(cases) {
+				t.Errorf("%d/%d passed, first failure %s", passed, len(cases), firstFailure)
+			}
+		})
+		summary = append(summary, fmt.Sprintf("%s %d/%d", opcode, passed, len(cases)))
+		totalPassed += passed
+		totalCases += len(cases)
+	}
+	return summary, totalPassed, totalCases
+}
+
+func TestSM83_fixture(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(sm83FixtureDir, "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("No single step tests found in %s", sm83FixtureDir)
+	}
+	sort.Strings(files)
+	runSM83Files(t, files)
+}
+
+func TestSM83(t *testing.T) {
+	dir := sm83TestDir()
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Skipf("No single step tests found in %s", dir)
+	}
+	if testing.Short() {
+		t.Skip("Skipping single step tests in short mode")
+	}
+	sort.Strings(files)
+
+	summary, totalPassed, totalCases := runSM83Files(t, files)
+	t.Logf("Passed %d/%d cases:\n%s", totalPassed, totalCases, strings.Join(summary, "\n"))
+}
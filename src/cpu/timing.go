@@ -0,0 +1,96 @@
+package cpu
+
+import (
+	"types"
+)
+
+// Advances the rest of the system (PPU, timers, DMA...) by a number of cycles
+type Ticker func(cycles int)
+
+// Every memory access takes one M-cycle
+const cyclesPerAccess = 4
+
+// Called with the cycles consumed as the CPU runs. Without cycle accurate
+// mode this happens once per Step, after the instruction has finished.
+func (c *Cpu) SetTicker(ticker Ticker) {
+	c.ticker = ticker
+}
+
+// In cycle accurate mode each memory access happens at the end of its own
+// M-cycle, and the ticker is called before every access so that the rest of
+// the system sees reads and writes at the right time within an instruction.
+func (c *Cpu) SetCycleAccurate(accurate bool) {
+	c.cycleAccurate = accurate
+}
+
+func (c *Cpu) CycleAccurate() bool {
+	return c.cycleAccurate
+}
+
+func (c *Cpu) tick(cycles int) {
+	c.cycles += uint64(cycles)
+	c.stepTicked += cycles
+	if c.ticker != nil {
+		c.ticker(cycles)
+	}
+}
+
+// An M-cycle where the CPU is busy but doesn't touch the bus. Only needed
+// where it comes before a memory access, any trailing ones are caught up by
+// finishStep.
+func (c *Cpu) internalCycle() {
+	if c.cycleAccurate {
+		c.tick(cyclesPerAccess)
+	}
+}
+
+func (c *Cpu) readByte(address types.Word) (byte, error) {
+	return c.accessByte(address, ReadAccess)
+}
+
+// Reads part of the instruction at PC, which observers see as an execute
+func (c *Cpu) fetchByte(address types.Word) (byte, error) {
+	return c.accessByte(address, ExecuteAccess)
+}
+
+func (c *Cpu) accessByte(address types.Word, kind AccessKind) (byte, error) {
+	if c.cycleAccurate {
+		c.tick(cyclesPerAccess)
+	}
+	value, err := c.memory.Get(address)
+	if err != nil {
+		c.fault(err)
+	}
+	if c.observers != nil {
+		c.notifyObservers(address, value, kind)
+	}
+	return value, err
+}
+
+func (c *Cpu) writeByte(address types.Word, value byte) error {
+	if c.cycleAccurate {
+		c.tick(cyclesPerAccess)
+	}
+	err := c.memory.Set(address, value)
+	if err != nil {
+		c.fault(err)
+	}
+	if c.observers != nil {
+		c.notifyObservers(address, value, WriteAccess)
+	}
+	return err
+}
+
+// Ticks whatever part of the step hasn't been accounted for by memory
+// accesses, which is all of it outside cycle accurate mode
+func (c *Cpu) finishStep(cycles int) int {
+	if c.cycleAccurate {
+		for c.stepTicked < cycles {
+			c.tick(cyclesPerAccess)
+		}
+	} else if remaining := cycles - c.stepTicked; remaining > 0 {
+		c.tick(remaining)
+	}
+	c.stepTicked = 0
+	return cycles
+}
@@ -0,0 +1,45 @@
+package cpu
+
+import (
+	"fmt"
+	"io"
+	"types"
+)
+
+// Logs the CPU state before every instruction, which is the same as after
+// each one plus the starting state. Lines are in Gameboy Doctor's format:
+//
+//   A:00 F:00 B:00 C:00 D:00 E:00 H:00 L:00 SP:0000 PC:0000 PCMEM:00,00,00,00
+//
+// Nothing is logged while halted or stopped, or for interrupt dispatch. A nil
+// writer turns tracing off. Files should be wrapped in a bufio.Writer as
+// there's a write per instruction.
+func (c *Cpu) SetTraceWriter(w io.Writer) {
+	c.traceWriter = w
+}
+
+// Current state as a Gameboy Doctor log line, without the newline
+func (c *Cpu) TraceLine() string {
+	r := c.Registers()
+	return fmt.Sprintf("A:%02X F:%02X B:%02X C:%02X D:%02X E:%02X H:%02X L:%02X SP:%04X PC:%04X PCMEM:%02X,%02X,%02X,%02X",
+		r.A, r.F, r.B, r.C, r.D, r.E, r.H, r.L, uint16(r.SP), uint16(r.PC),
+		c.peekByte(r.PC), c.peekByte(r.PC + 1), c.peekByte(r.PC + 2), c.peekByte(r.PC + 3))
+}
+
+// Reads memory without ticking the system, for debugging. Addresses past the
+// end of memory read as 0
+func (c *Cpu) peekByte(address types.Word) byte {
+	value, err := c.memory.Get(address)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+func (c *Cpu) trace() error {
+	if c.traceWriter == nil {
+		return nil
+	}
+	_, err := fmt.Fprintln(c.traceWriter, c.TraceLine())
+	return err
+}
@@ -0,0 +1,58 @@
+package cpu
+
+import (
+	"bytes"
+	"memory"
+	"strings"
+	"testing"
+	"types"
+)
+
+func TestTrace(t *testing.T) {
+	mem := memory.SetupBlankMemory(0x10000)
+	cpu := NewCpu(mem)
+	cpu.PC.Assign(types.Word(0x100))
+	cpu.SP.Assign(types.Word(0xFFFE))
+	cpu.F.Assign(0xB0)
+	// NOP, LD B,$12, HALT
+	for offset, val := range []byte{0x00, 0x06, 0x12, 0x76} {
+		mem.Set(types.Word(0x100 + offset), val)
+	}
+
+	var log bytes.Buffer
+	cpu.SetTraceWriter(&log)
+	for i := 0; i < 4; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	expected := []string{
+		"A:00 F:B0 B:00 C:00 D:00 E:00 H:00 L:00 SP:FFFE PC:0100 PCMEM:00,06,12,76",
+		"A:00 F:B0 B:00 C:00 D:00 E:00 H:00 L:00 SP:FFFE PC:0101 PCMEM:06,12,76,00",
+		"A:00 F:B0 B:12 C:00 D:00 E:00 H:00 L:00 SP:FFFE PC:0103 PCMEM:76,00,00,00",
+	}
+	// The fourth step is spent halted so isn't logged
+	if got := strings.Split(strings.TrimSpace(log.String()), "\n"); strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected trace, want:\n%s\ngot:\n%s", strings.Join(expected, "\n"), log.String())
+	}
+
+	log.Reset()
+	cpu.SetTraceWriter(nil)
+	cpu.RequestInterrupt(VBlank)
+	cpu.SetInterruptEnable(0x01)
+	if _, err := cpu.Step(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if log.Len() != 0 {
+		t.Errorf("Expected nothing logged once tracing is off, got %q", log.String())
+	}
+}
+
+func TestTraceLine_pastEndOfMemory(t *testing.T) {
+	cpu, _ := setupCpu()
+	cpu.PC.Assign(types.Word(mockMemorySize - 2))
+	if line := cpu.TraceLine(); !strings.HasSuffix(line, "PCMEM:00,00,00,00") {
+		t.Errorf("Expected memory past the end to read as 0, got %s", line)
+	}
+}
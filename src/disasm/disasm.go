@@ -0,0 +1,145 @@
+// Decodes instructions without executing them, using the CPU's instruction
+// spec for mnemonics and lengths
+package disasm
+
+import (
+	"cpu"
+	"fmt"
+	"strings"
+	"types"
+)
+
+// Anything bytes can be read from, e.g. memory.Memory
+type Reader interface {
+	Get(address types.Word) (byte, error)
+}
+
+// Adapts a byte slice to a Reader. Reads past the end return an error
+// instead of panicking
+type Bytes []byte
+
+func (b Bytes) Get(address types.Word) (byte, error) {
+	if int(address) >= len(b) {
+		return 0, fmt.Errorf("Address %s past end of %d bytes", address, len(b))
+	}
+	return b[address], nil
+}
+
+type Instruction struct {
+	Address types.Word
+	// Raw bytes, including the 0xCB prefix and any operands
+	Bytes []byte
+	// Mnemonic with operands filled in, e.g. "LD B,$3C" or "JR NZ,$0150"
+	Text string
+	Length int
+	// Address of the following instruction
+	Next types.Word
+}
+
+func (i Instruction) String() string {
+	return fmt.Sprintf("%04X: %s", uint16(i.Address), i.Text)
+}
+
+// Decodes the instruction at address. Illegal opcodes come back as a DB
+// directive so the output still assembles
+func Disassemble(mem Reader, address types.Word) (Instruction, error) {
+	inst := Instruction{Address: address}
+	read := func(offset int) (byte, error) {
+		value, err := mem.Get(address + types.Word(offset))
+		if err != nil {
+			return 0, err
+		}
+		inst.Bytes = append(inst.Bytes, value)
+		return value, nil
+	}
+
+	code, err := read(0)
+	if err != nil {
+		return inst, err
+	}
+	spec := cpu.MainInstructions[code]
+	operandOffset := 1
+	if code == 0xCB {
+		cbCode, err := read(1)
+		if err != nil {
+			return inst, err
+		}
+		spec = cpu.CBInstructions[cbCode]
+		operandOffset = 2
+	}
+	inst.Length = spec.Length
+	inst.Next = address + types.Word(spec.Length)
+	if spec.Illegal() {
+		inst.Text = fmt.Sprintf("DB $%02X", code)
+		return inst, nil
+	}
+
+	// Operand bytes, including the padding byte after STOP which has no
+	// placeholder in the mnemonic
+	var operand []byte
+	for offset := operandOffset; offset < spec.Length; offset++ {
+		value, err := read(offset)
+		if err != nil {
+			return inst, err
+		}
+		operand = append(operand, value)
+	}
+	inst.Text = resolveOperands(spec.Mnemonic, operand, inst.Next)
+	return inst, nil
+}
+
+// Decodes count instructions starting from address
+func DisassembleCount(mem Reader, address types.Word, count int) ([]Instruction, error) {
+	var instructions []Instruction
+	for i := 0; i < count; i++ {
+		inst, err := Disassemble(mem, address)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, inst)
+		address = inst.Next
+	}
+	return instructions, nil
+}
+
+// Replaces the operand placeholders in a spec mnemonic with the values that
+// follow the opcode
+func resolveOperands(mnemonic string, operand []byte, next types.Word) string {
+	word := func() types.Word {
+		return types.WordFromBytes(operand[0], operand[1])
+	}
+	signed := func() string {
+		offset := int(int8(operand[0]))
+		if offset < 0 {
+			return fmt.Sprintf("-$%02X", -offset)
+		}
+		return fmt.Sprintf("$%02X", offset)
+	}
+
+	switch {
+	case strings.HasPrefix(mnemonic, "RST"):
+		// The spec writes the vector as e.g. 38H
+		return "RST $" + strings.TrimSuffix(strings.TrimPrefix(mnemonic, "RST "), "H")
+	case strings.Contains(mnemonic, "d16"):
+		return strings.Replace(mnemonic, "d16", fmt.Sprintf("$%04X", uint16(word())), 1)
+	case strings.Contains(mnemonic, "a16"):
+		return strings.Replace(mnemonic, "a16", fmt.Sprintf("$%04X", uint16(word())), 1)
+	case strings.Contains(mnemonic, "d8"):
+		return strings.Replace(mnemonic, "d8", fmt.Sprintf("$%02X", operand[0]), 1)
+	case strings.Contains(mnemonic, "a8"):
+		return strings.Replace(mnemonic, "a8", fmt.Sprintf("$FF%02X", operand[0]), 1)
+	case strings.HasPrefix(mnemonic, "JR"):
+		// Relative jumps show the resolved target
+		target := next + types.Word(int8(operand[0]))
+		return strings.Replace(mnemonic, "r8", fmt.Sprintf("$%04X", uint16(target)), 1)
+	case strings.Contains(mnemonic, "SP+r8"):
+		offset := signed()
+		if !strings.HasPrefix(offset, "-") {
+			offset = "+" + offset
+		}
+		return strings.Replace(mnemonic, "+r8", offset, 1)
+	case strings.Contains(mnemonic, "r8"):
+		return strings.Replace(mnemonic, "r8", signed(), 1)
+	}
+	return mnemonic
+}
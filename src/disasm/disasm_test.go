@@ -0,0 +1,71 @@
+package disasm
+
+import (
+	"memory"
+	"testing"
+	"types"
+)
+
+func TestDisassemble(t *testing.T) {
+	tests := []struct {
+		name string
+		address types.Word
+		bytes []byte
+		text string
+		length int
+	}{
+		{"no operands", 0x100, []byte{0x00}, "NOP", 1},
+		{"8 bit immediate", 0x100, []byte{0x06, 0x3C}, "LD B,$3C", 2},
+		{"16 bit immediate", 0x100, []byte{0x21, 0x34, 0x12}, "LD HL,$1234", 3},
+		{"absolute address", 0x100, []byte{0xEA, 0x00, 0xC0}, "LD ($C000),A", 3},
+		{"conditional jump", 0x100, []byte{0xC2, 0x50, 0x01}, "JP NZ,$0150", 3},
+		{"relative jump forwards", 0x140, []byte{0x20, 0x0E}, "JR NZ,$0150", 2},
+		{"relative jump backwards", 0x150, []byte{0x18, 0xFE}, "JR $0150", 2},
+		{"high page", 0x100, []byte{0xE0, 0x44}, "LDH ($FF44),A", 2},
+		{"signed SP offset", 0x100, []byte{0xE8, 0xFB}, "ADD SP,-$05", 2},
+		{"SP offset load", 0x100, []byte{0xF8, 0x05}, "LD HL,SP+$05", 2},
+		{"stop", 0x100, []byte{0x10, 0x00}, "STOP 0", 2},
+		{"restart", 0x100, []byte{0xFF}, "RST $38", 1},
+		{"cb prefixed", 0x100, []byte{0xCB, 0x7C}, "BIT 7,H", 2},
+		{"cb prefixed memory", 0x100, []byte{0xCB, 0x36}, "SWAP (HL)", 2},
+		{"illegal", 0x100, []byte{0xD3}, "DB $D3", 1},
+	}
+	for _, test := range tests {
+		mem := memory.SetupBlankMemory(0x10000)
+		for i, value := range test.bytes {
+			mem.Set(test.address + types.Word(i), value)
+		}
+		inst, err := Disassemble(mem, test.address)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if inst.Text != test.text {
+			t.Errorf("%s: want %s, got %s", test.name, test.text, inst.Text)
+		}
+		if inst.Length != test.length || len(inst.Bytes) != test.length {
+			t.Errorf("%s: want length %d, got %d with bytes % X", test.name, test.length, inst.Length, inst.Bytes)
+		}
+		if inst.Next != test.address + types.Word(test.length) {
+			t.Errorf("%s: want next %s, got %s", test.name, test.address + types.Word(test.length), inst.Next)
+		}
+	}
+}
+
+func TestDisassembleBytes(t *testing.T) {
+	program := Bytes{0x3E, 0x01, 0xCB, 0x27, 0xC3, 0x00}
+	instructions, err := DisassembleCount(program, 0, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if instructions[0].Text != "LD A,$01" || instructions[1].Text != "SLA A" {
+		t.Errorf("Unexpected instructions %v", instructions)
+	}
+	if instructions[1].String() != "0002: SLA A" {
+		t.Errorf("Unexpected String() %s", instructions[1])
+	}
+	// JP a16 runs off the end of the slice
+	if _, err := Disassemble(program, 4); err == nil {
+		t.Errorf("Expected an error for a truncated instruction")
+	}
+}
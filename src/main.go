@@ -2,11 +2,43 @@ package main
 
 import (
 	"cpu"
+	"flag"
+	"fmt"
 	"memory"
+	"os"
 )
 
 func main() {
 	mem := memory.InitializeMainMemory()
 	c := cpu.NewCpu(mem)
+	if err := c.RegisterInterrupts(mem); err != nil {
+		fmt.Fprintf(os.Stderr, "game-toy: %v\n", err)
+		os.Exit(1)
+	}
+	c.SetTicker(mem.Tick)
+
+	if len(os.Args) > 1 && os.Args[1] == "coverage" {
+		if err := coverage(c, os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "coverage: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 	c.PrintKnownOpCodes()
 }
+
+// Writes the opcode coverage report, e.g. `game-toy coverage -format csv`
+func coverage(c *cpu.Cpu, args []string) error {
+	flags := flag.NewFlagSet("coverage", flag.ExitOnError)
+	format := flags.String("format", "json", "Output format, json or csv")
+	flags.Parse(args)
+
+	report := c.Coverage()
+	switch *format {
+	case "json":
+		return report.WriteJSON(os.Stdout)
+	case "csv":
+		return report.WriteCSV(os.Stdout)
+	}
+	return fmt.Errorf("Unknown format %q", *format)
+}
@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"types"
+)
+
+// Start of each region in the memory map
+const (
+	RomBank0Start types.Word = 0x0000
+	RomBankNStart types.Word = 0x4000
+	VideoRamStart types.Word = 0x8000
+	ExternalRamStart types.Word = 0xA000
+	WorkRamStart types.Word = 0xC000
+	EchoRamStart types.Word = 0xE000
+	OamStart types.Word = 0xFE00
+	UnusableStart types.Word = 0xFEA0
+	IoStart types.Word = 0xFF00
+	HighRamStart types.Word = 0xFF80
+	InterruptEnableAddress types.Word = 0xFFFF
+)
+
+const (
+	romBankSize = 0x4000
+	videoRamSize = 0x2000
+	externalRamBankSize = 0x2000
+	workRamSize = 0x2000
+	oamSize = 0xA0
+	ioSize = 0x80
+	highRamSize = 0x7F
+)
+
+// What reads of unmapped addresses return, as nothing drives the bus
+const openBus = 0xFF
+
+// Routes the whole 16 bit address space to the cartridge and the console's
+// own memories. I/O registers and IE go to the Device registered for them,
+// and are plain storage when there isn't one
+type Bus struct {
+	// Cartridge ROM, every bank. Bank 0 is fixed at 0x0000 and romBank is
+	// mapped at 0x4000
+	rom []byte
+	romBank int
+	// Cartridge RAM, nil when there isn't any
+	externalRam []byte
+	ramBank int
+	ramEnabled bool
+	// Nil for cartridges that are just ROM
+	controller Controller
+	videoRam [videoRamSize]byte
+	workRam [workRamSize]byte
+	oam [oamSize]byte
+	io [ioSize]byte
+	highRam [highRamSize]byte
+	interruptEnable byte
+	// I/O registers then IE, see deviceIndex
+	devices [ioSize + 1]Device
+	readMasks [ioSize + 1]byte
+	dma dma
+}
+
+func NewBus() *Bus {
+	return &Bus{romBank: 1}
+}
+
+// A cartridge's memory bank controller. It's handed every write to ROM
+// (0x0000-0x7FFF) and external RAM (0xA000-0xBFFF), and switches banks with
+// SetRomBank, SetRamBank and SetRamEnabled
+type Controller interface {
+	Write(address types.Word, value byte)
+}
+
+// Maps a cartridge's ROM and RAM. ram may be nil for cartridges without any,
+// and controller nil for cartridges without one, where ROM writes are
+// ignored. Controllers start with RAM disabled, like the hardware
+func (b *Bus) LoadCartridge(rom []byte, ram []byte, controller Controller) {
+	b.rom = rom
+	b.externalRam = ram
+	b.controller = controller
+	b.romBank = 1
+	b.ramBank = 0
+	b.ramEnabled = controller == nil
+}
+
+// Switches the ROM bank at 0x4000, for the cartridge's bank controller
+func (b *Bus) SetRomBank(bank int) {
+	b.romBank = bank
+}
+
+// Switches the external RAM bank at 0xA000
+func (b *Bus) SetRamBank(bank int) {
+	b.ramBank = bank
+}
+
+// While disabled external RAM reads as open bus and ignores writes
+func (b *Bus) SetRamEnabled(enabled bool) {
+	b.ramEnabled = enabled
+}
+
+// Offset into a banked memory, or -1 when the bank doesn't exist
+func bankOffset(data []byte, bank int, bankSize int, offset types.Word) int {
+	index := bank * bankSize + int(offset)
+	if index >= len(data) {
+		return -1
+	}
+	return index
+}
+
+// While an OAM DMA transfer is running, reads outside HRAM and I/O return
+// the byte being copied
+func (b *Bus) Get(address types.Word) (byte, error) {
+	if b.dmaBlocks(address) {
+		return b.dma.value, nil
+	}
+	return b.read(address), nil
+}
+
+func (b *Bus) read(address types.Word) byte {
+	switch {
+	case address < RomBankNStart:
+		if index := bankOffset(b.rom, 0, romBankSize, address); index >= 0 {
+			return b.rom[index]
+		}
+	case address < VideoRamStart:
+		if index := bankOffset(b.rom, b.romBank, romBankSize, address - RomBankNStart); index >= 0 {
+			return b.rom[index]
+		}
+	case address < ExternalRamStart:
+		return b.videoRam[address - VideoRamStart]
+	case address < WorkRamStart:
+		if !b.ramEnabled {
+			break
+		}
+		if index := bankOffset(b.externalRam, b.ramBank, externalRamBankSize, address - ExternalRamStart); index >= 0 {
+			return b.externalRam[index]
+		}
+	case address < EchoRamStart:
+		return b.workRam[address - WorkRamStart]
+	case address < OamStart:
+		// Echo RAM mirrors 0xC000-0xDDFF
+		return b.workRam[address - EchoRamStart]
+	case address < UnusableStart:
+		return b.oam[address - OamStart]
+	case address < IoStart:
+		// Unusable, nothing answers
+	case address < HighRamStart:
+		return b.readDevice(address, b.io[address - IoStart])
+	case address < InterruptEnableAddress:
+		return b.highRam[address - HighRamStart]
+	default:
+		return b.readDevice(address, b.interruptEnable)
+	}
+	return openBus
+}
+
+// Writes to ROM go to the cartridge's controller, if it has one. Writes to
+// unmapped areas are dropped, as are writes outside HRAM and I/O during OAM
+// DMA
+func (b *Bus) Set(address types.Word, value byte) error {
+	if b.dmaBlocks(address) {
+		return nil
+	}
+	switch {
+	case address < VideoRamStart:
+		if b.controller != nil {
+			b.controller.Write(address, value)
+		}
+	case address < ExternalRamStart:
+		b.videoRam[address - VideoRamStart] = value
+	case address < WorkRamStart:
+		if b.controller != nil {
+			b.controller.Write(address, value)
+		}
+		if !b.ramEnabled {
+			break
+		}
+		if index := bankOffset(b.externalRam, b.ramBank, externalRamBankSize, address - ExternalRamStart); index >= 0 {
+			b.externalRam[index] = value
+		}
+	case address < EchoRamStart:
+		b.workRam[address - WorkRamStart] = value
+	case address < OamStart:
+		b.workRam[address - EchoRamStart] = value
+	case address < UnusableStart:
+		b.oam[address - OamStart] = value
+	case address < IoStart:
+	case address < HighRamStart:
+		if address == DmaAddress {
+			b.startDma(value)
+		}
+		if !b.writeDevice(address, value) {
+			b.io[address - IoStart] = value
+		}
+	case address < InterruptEnableAddress:
+		b.highRam[address - HighRamStart] = value
+	default:
+		if !b.writeDevice(address, value) {
+			b.interruptEnable = value
+		}
+	}
+	return nil
+}
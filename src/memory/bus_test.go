@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"testing"
+	"types"
+)
+
+func TestBus_regions(t *testing.T) {
+	bus := NewBus()
+	// Four ROM banks, each filled with its bank number, and two RAM banks
+	rom := make([]byte, 4 * romBankSize)
+	for i := range rom {
+		rom[i] = byte(i / romBankSize)
+	}
+	bus.LoadCartridge(rom, make([]byte, 2 * externalRamBankSize), nil)
+
+	// Every writable region reads back what was written, ROM doesn't
+	tests := []struct {
+		name string
+		address types.Word
+		before, after byte
+	}{
+		{"ROM bank 0", 0x0000, 0x00, 0x00},
+		{"ROM bank 0 end", 0x3FFF, 0x00, 0x00},
+		{"ROM bank 1", 0x4000, 0x01, 0x01},
+		{"VRAM", 0x8000, 0x00, 0x42},
+		{"VRAM end", 0x9FFF, 0x00, 0x42},
+		{"external RAM", 0xA000, 0x00, 0x42},
+		{"WRAM", 0xC000, 0x00, 0x42},
+		{"WRAM end", 0xDFFF, 0x00, 0x42},
+		{"OAM", 0xFE00, 0x00, 0x42},
+		{"OAM end", 0xFE9F, 0x00, 0x42},
+		{"unusable", 0xFEA0, 0xFF, 0xFF},
+		{"unusable end", 0xFEFF, 0xFF, 0xFF},
+		{"I/O", 0xFF00, 0x00, 0x42},
+		{"HRAM", 0xFF80, 0x00, 0x42},
+		{"HRAM end", 0xFFFE, 0x00, 0x42},
+		{"IE", 0xFFFF, 0x00, 0x42},
+	}
+	for _, test := range tests {
+		if got, err := bus.Get(test.address); err != nil || got != test.before {
+			t.Errorf("%s: want 0x%02X before writing, got 0x%02X (%v)", test.name, test.before, got, err)
+		}
+		if err := bus.Set(test.address, 0x42); err != nil {
+			t.Errorf("%s: unexpected error writing: %v", test.name, err)
+		}
+		if got, _ := bus.Get(test.address); got != test.after {
+			t.Errorf("%s: want 0x%02X after writing, got 0x%02X", test.name, test.after, got)
+		}
+	}
+}
+
+func TestBus_echoRam(t *testing.T) {
+	bus := NewBus()
+	bus.Set(0xC123, 0x12)
+	if got, _ := bus.Get(0xE123); got != 0x12 {
+		t.Errorf("Expected echo RAM to mirror WRAM, got 0x%02X", got)
+	}
+	bus.Set(0xFDFF, 0x34)
+	if got, _ := bus.Get(0xDDFF); got != 0x34 {
+		t.Errorf("Expected writes to echo RAM to reach WRAM, got 0x%02X", got)
+	}
+	// Echo RAM stops short of OAM
+	bus.Set(0xDE00, 0x56)
+	if got, _ := bus.Get(0xFE00); got == 0x56 {
+		t.Errorf("Expected OAM not to mirror WRAM")
+	}
+}
+
+func TestBus_banking(t *testing.T) {
+	bus := NewBus()
+	rom := make([]byte, 4 * romBankSize)
+	for i := range rom {
+		rom[i] = byte(i / romBankSize)
+	}
+	ram := make([]byte, 2 * externalRamBankSize)
+	bus.LoadCartridge(rom, ram, nil)
+
+	bus.SetRomBank(3)
+	if got, _ := bus.Get(0x4000); got != 3 {
+		t.Errorf("Expected ROM bank 3, got %d", got)
+	}
+	if got, _ := bus.Get(0x0000); got != 0 {
+		t.Errorf("Expected bank 0 to stay fixed, got %d", got)
+	}
+	// Banks past the end of the ROM aren't there
+	bus.SetRomBank(8)
+	if got, _ := bus.Get(0x4000); got != openBus {
+		t.Errorf("Expected open bus past the end of ROM, got 0x%02X", got)
+	}
+
+	bus.SetRamBank(1)
+	bus.Set(0xA010, 0x99)
+	if ram[externalRamBankSize + 0x10] != 0x99 || ram[0x10] != 0x00 {
+		t.Errorf("Expected the write to land in RAM bank 1")
+	}
+}
+
+func TestBus_noCartridge(t *testing.T) {
+	bus := InitializeMainMemory()
+	for _, address := range []types.Word{0x0000, 0x4000, 0x7FFF, 0xA000, 0xBFFF} {
+		bus.Set(address, 0x00)
+		if got, _ := bus.Get(address); got != openBus {
+			t.Errorf("Expected open bus at %s with no cartridge, got 0x%02X", address, got)
+		}
+	}
+}
+
+// Bank switching along the lines of an MBC1: RAM enable at 0x0000, ROM bank
+// at 0x2000 and RAM bank at 0x4000
+type testController struct {
+	bus *Bus
+	writes int
+}
+
+func (c *testController) Write(address types.Word, value byte) {
+	c.writes++
+	switch {
+	case address < 0x2000:
+		c.bus.SetRamEnabled(value & 0x0F == 0x0A)
+	case address < 0x4000:
+		c.bus.SetRomBank(int(value))
+	case address < 0x6000:
+		c.bus.SetRamBank(int(value))
+	}
+}
+
+func TestBus_controller(t *testing.T) {
+	bus := NewBus()
+	rom := make([]byte, 4 * romBankSize)
+	for i := range rom {
+		rom[i] = byte(i / romBankSize)
+	}
+	ram := make([]byte, 2 * externalRamBankSize)
+	controller := &testController{bus: bus}
+	bus.LoadCartridge(rom, ram, controller)
+
+	bus.Set(0x2000, 0x02)
+	if got, _ := bus.Get(0x4000); got != 2 {
+		t.Errorf("Expected the controller to switch to ROM bank 2, got %d", got)
+	}
+	if got, _ := bus.Get(0x2000); got != 0 {
+		t.Errorf("Expected the ROM to be left alone, got 0x%02X", got)
+	}
+
+	// RAM starts out disabled
+	bus.Set(0xA000, 0x12)
+	if got, _ := bus.Get(0xA000); got != openBus || ram[0] != 0x00 {
+		t.Errorf("Expected disabled RAM to read open bus and drop writes, got 0x%02X", got)
+	}
+	bus.Set(0x0000, 0x0A)
+	bus.Set(0x4000, 0x01)
+	bus.Set(0xA000, 0x34)
+	if got, _ := bus.Get(0xA000); got != 0x34 || ram[externalRamBankSize] != 0x34 {
+		t.Errorf("Expected the write to land in RAM bank 1, got 0x%02X", got)
+	}
+	// Every ROM and external RAM write reaches the controller
+	if controller.writes != 5 {
+		t.Errorf("Expected the controller to see 5 writes, got %d", controller.writes)
+	}
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"fmt"
+	"types"
+)
+
+// A component that owns some of the memory mapped registers, e.g. the PPU,
+// timer or joypad. Handlers get the full address so one device can cover a
+// range of registers
+type Device interface {
+	Read(address types.Word) byte
+	Write(address types.Word, value byte)
+}
+
+// Returned by RegisterDevice when the range can't be given to the device
+type DeviceRangeError struct {
+	Start, End types.Word
+	Reason string
+}
+
+func (e *DeviceRangeError) Error() string {
+	return fmt.Sprintf("Can't register device for %s-%s: %s", e.Start, e.End, e.Reason)
+}
+
+// Devices live in I/O (0xFF00-0xFF7F) and IE, which sits right after it in
+// the device tables
+func deviceIndex(address types.Word) (int, bool) {
+	switch {
+	case address >= IoStart && address < HighRamStart:
+		return int(address - IoStart), true
+	case address == InterruptEnableAddress:
+		return ioSize, true
+	}
+	return 0, false
+}
+
+// Hands reads and writes of start through end, inclusive, to device. Only
+// the I/O registers and IE can be owned by a device, and each address by
+// only one
+func (b *Bus) RegisterDevice(start types.Word, end types.Word, device Device) error {
+	if end < start {
+		return &DeviceRangeError{start, end, "end is before start"}
+	}
+	for address := uint32(start); address <= uint32(end); address++ {
+		index, ok := deviceIndex(types.Word(address))
+		if !ok {
+			return &DeviceRangeError{start, end, fmt.Sprintf("%s is not an I/O register", types.Word(address))}
+		}
+		if b.devices[index] != nil {
+			return &DeviceRangeError{start, end, fmt.Sprintf("%s is already registered", types.Word(address))}
+		}
+	}
+	for address := uint32(start); address <= uint32(end); address++ {
+		index, _ := deviceIndex(types.Word(address))
+		b.devices[index] = device
+	}
+	return nil
+}
+
+// Bits set in mask always read back as 1, for registers with unused bits.
+// Applies whether or not a device owns the address
+func (b *Bus) SetReadMask(address types.Word, mask byte) error {
+	index, ok := deviceIndex(address)
+	if !ok {
+		return &DeviceRangeError{address, address, fmt.Sprintf("%s is not an I/O register", address)}
+	}
+	b.readMasks[index] = mask
+	return nil
+}
+
+func (b *Bus) readDevice(address types.Word, stored byte) byte {
+	index, _ := deviceIndex(address)
+	if device := b.devices[index]; device != nil {
+		stored = device.Read(address)
+	}
+	return stored | b.readMasks[index]
+}
+
+// Returns whether a device took the write
+func (b *Bus) writeDevice(address types.Word, value byte) bool {
+	index, _ := deviceIndex(address)
+	if device := b.devices[index]; device != nil {
+		device.Write(address, value)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"testing"
+	"types"
+)
+
+// Records writes and reads back a fixed value
+type testDevice struct {
+	value byte
+	writes map[types.Word]byte
+}
+
+func (d *testDevice) Read(address types.Word) byte {
+	return d.value
+}
+
+func (d *testDevice) Write(address types.Word, value byte) {
+	d.writes[address] = value
+}
+
+func TestBus_devices(t *testing.T) {
+	bus := NewBus()
+	device := &testDevice{value: 0x05, writes: map[types.Word]byte{}}
+	if err := bus.RegisterDevice(0xFF40, 0xFF4B, device); err != nil {
+		t.Fatalf("Unexpected error registering device: %v", err)
+	}
+
+	for _, address := range []types.Word{0xFF40, 0xFF45, 0xFF4B} {
+		if got, _ := bus.Get(address); got != 0x05 {
+			t.Errorf("Expected the device to answer reads of %s, got 0x%02X", address, got)
+		}
+		bus.Set(address, byte(address))
+		if device.writes[address] != byte(address) {
+			t.Errorf("Expected the device to get writes to %s", address)
+		}
+	}
+	// Outside the range is still plain storage
+	bus.Set(0xFF4C, 0x12)
+	if got, _ := bus.Get(0xFF4C); got != 0x12 || len(device.writes) != 3 {
+		t.Errorf("Expected 0xFF4C to bypass the device, got 0x%02X", got)
+	}
+}
+
+func TestBus_readMasks(t *testing.T) {
+	bus := NewBus()
+	device := &testDevice{value: 0x05, writes: map[types.Word]byte{}}
+	bus.RegisterDevice(0xFF0F, 0xFF0F, device)
+	bus.SetReadMask(0xFF0F, 0xE0)
+	if got, _ := bus.Get(0xFF0F); got != 0xE5 {
+		t.Errorf("Expected masked bits to read as 1, want 0xE5, got 0x%02X", got)
+	}
+
+	// Masks work without a device too
+	bus.SetReadMask(0xFF41, 0x80)
+	bus.Set(0xFF41, 0x01)
+	if got, _ := bus.Get(0xFF41); got != 0x81 {
+		t.Errorf("Expected 0x81, got 0x%02X", got)
+	}
+}
+
+func TestBus_registerDeviceErrors(t *testing.T) {
+	bus := NewBus()
+	device := &testDevice{writes: map[types.Word]byte{}}
+	bus.RegisterDevice(0xFF04, 0xFF07, device)
+
+	tests := []struct {
+		name string
+		start, end types.Word
+	}{
+		{"overlap", 0xFF07, 0xFF08},
+		{"WRAM", 0xC000, 0xC000},
+		{"into HRAM", 0xFF7F, 0xFF80},
+		{"backwards", 0xFF20, 0xFF10},
+	}
+	for _, test := range tests {
+		if err := bus.RegisterDevice(test.start, test.end, device); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+	// Nothing is registered by a failed call
+	bus.Set(0xFF08, 0x34)
+	if got, _ := bus.Get(0xFF08); got != 0x34 {
+		t.Errorf("Expected 0xFF08 to be left unregistered, got 0x%02X", got)
+	}
+	if err := bus.SetReadMask(0xC000, 0xFF); err == nil {
+		t.Errorf("Expected an error masking WRAM")
+	}
+	// IE can be owned too
+	if err := bus.RegisterDevice(0xFFFF, 0xFFFF, device); err != nil {
+		t.Errorf("Unexpected error registering IE: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"types"
+)
+
+// Writing the high byte of a source address here copies 0xXX00-0xXX9F to OAM
+const DmaAddress types.Word = 0xFF46
+
+const (
+	// One byte is copied per M-cycle
+	dmaCyclesPerByte = 4
+	// M-cycles between the write to DMA and the first byte being copied
+	dmaStartDelay = 1
+)
+
+// State of an OAM DMA transfer. A write to DMA while a transfer is running
+// restarts it, but the old one keeps going until the new one starts
+type dma struct {
+	active bool
+	source types.Word
+	// Bytes copied so far
+	index int
+	// The last byte copied, which is what the CPU sees on the bus
+	value byte
+	// A transfer waiting to start, and the M-cycles left until it does
+	pending bool
+	pendingSource types.Word
+	delay int
+	// T-cycles not yet making up a whole M-cycle
+	cycles int
+}
+
+func (b *Bus) startDma(value byte) {
+	b.dma.pending = true
+	b.dma.pendingSource = types.Word(value) << 8
+	b.dma.delay = dmaStartDelay
+	b.dma.cycles = 0
+}
+
+// Whether a transfer is running, during which the CPU can only reach HRAM
+// and the I/O registers
+func (b *Bus) DmaActive() bool {
+	return b.dma.active
+}
+
+// Advances any DMA transfer. Takes T-cycles so it can be the CPU's ticker
+func (b *Bus) Tick(cycles int) {
+	if !b.dma.active && !b.dma.pending {
+		return
+	}
+	b.dma.cycles += cycles
+	for b.dma.cycles >= dmaCyclesPerByte {
+		b.dma.cycles -= dmaCyclesPerByte
+		b.dmaStep()
+	}
+}
+
+// One M-cycle of DMA
+func (b *Bus) dmaStep() {
+	if b.dma.pending && b.dma.delay == 0 {
+		b.dma.pending = false
+		b.dma.active = true
+		b.dma.source = b.dma.pendingSource
+		b.dma.index = 0
+	}
+	if b.dma.active {
+		b.dma.value = b.dmaRead(b.dma.source + types.Word(b.dma.index))
+		b.oam[b.dma.index] = b.dma.value
+		b.dma.index++
+		if b.dma.index == oamSize {
+			b.dma.active = false
+		}
+	}
+	if b.dma.pending {
+		b.dma.delay--
+	}
+}
+
+// DMA reads bypass its own blocking. Sources from 0xE000 up all read work
+// RAM, as the top bits of the address are ignored
+func (b *Bus) dmaRead(address types.Word) byte {
+	if address >= EchoRamStart {
+		return b.workRam[address - EchoRamStart]
+	}
+	return b.read(address)
+}
+
+// The CPU's own bus is cut off from everything but HRAM and I/O
+func (b *Bus) dmaBlocks(address types.Word) bool {
+	return b.dma.active && address < IoStart
+}
@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"testing"
+	"types"
+)
+
+// Fills 160 bytes from source with offset+i so each byte is distinct
+func fillDmaSource(bus *Bus, source types.Word, offset byte) {
+	for i := 0; i < oamSize; i++ {
+		bus.Set(source + types.Word(i), offset + byte(i))
+	}
+}
+
+// Ticks the bus a number of M-cycles
+func tickM(bus *Bus, count int) {
+	for i := 0; i < count; i++ {
+		bus.Tick(dmaCyclesPerByte)
+	}
+}
+
+func TestDma_transfer(t *testing.T) {
+	bus := NewBus()
+	fillDmaSource(bus, 0xC100, 0x10)
+	bus.Set(DmaAddress, 0xC1)
+	if got, _ := bus.Get(DmaAddress); got != 0xC1 {
+		t.Errorf("Expected DMA to read back 0xC1, got 0x%02X", got)
+	}
+
+	// Nothing is blocked during the start up delay
+	tickM(bus, dmaStartDelay)
+	if got, _ := bus.Get(0xC100); got != 0x10 || bus.DmaActive() {
+		t.Fatalf("Expected the bus to be free before the first byte, got 0x%02X", got)
+	}
+
+	tickM(bus, 3)
+	if !bus.DmaActive() {
+		t.Fatalf("Expected DMA to be running")
+	}
+	// Everything but HRAM and I/O reads the byte being transferred
+	for _, address := range []types.Word{0x0000, 0x8000, 0xC100, 0xFE00} {
+		if got, _ := bus.Get(address); got != 0x12 {
+			t.Errorf("Expected the byte being transferred reading %s, got 0x%02X", address, got)
+		}
+	}
+	bus.Set(0xC1F0, 0x99)
+	bus.Set(0xFF80, 0x42)
+	if got, _ := bus.Get(0xFF80); got != 0x42 {
+		t.Errorf("Expected HRAM to be reachable during DMA, got 0x%02X", got)
+	}
+	if got, _ := bus.Get(DmaAddress); got != 0xC1 {
+		t.Errorf("Expected I/O to be reachable during DMA, got 0x%02X", got)
+	}
+
+	// 160 M-cycles in all
+	tickM(bus, oamSize - 4)
+	if !bus.DmaActive() {
+		t.Fatalf("Expected DMA to still be running before the last byte")
+	}
+	tickM(bus, 1)
+	if bus.DmaActive() {
+		t.Fatalf("Expected DMA to be done after 160 M-cycles")
+	}
+	for i := 0; i < oamSize; i++ {
+		if got, _ := bus.Get(OamStart + types.Word(i)); got != 0x10 + byte(i) {
+			t.Fatalf("OAM byte %d: want 0x%02X, got 0x%02X", i, 0x10 + byte(i), got)
+		}
+	}
+	if got, _ := bus.Get(0xC1F0); got != 0x00 {
+		t.Errorf("Expected the write during DMA to be dropped, got 0x%02X", got)
+	}
+}
+
+func TestDma_restart(t *testing.T) {
+	bus := NewBus()
+	fillDmaSource(bus, 0xC000, 0x00)
+	fillDmaSource(bus, 0xD000, 0x80)
+	bus.Set(DmaAddress, 0xC0)
+	tickM(bus, dmaStartDelay + 10)
+
+	// The old transfer carries on through the new one's start up delay
+	bus.Set(DmaAddress, 0xD0)
+	tickM(bus, dmaStartDelay)
+	if got, _ := bus.Get(0x0000); got != 0x0A {
+		t.Errorf("Expected the old transfer's byte during the delay, got 0x%02X", got)
+	}
+	tickM(bus, 1)
+	if got, _ := bus.Get(0x0000); got != 0x80 {
+		t.Errorf("Expected the new transfer's first byte, got 0x%02X", got)
+	}
+	tickM(bus, oamSize - 1)
+	if bus.DmaActive() {
+		t.Fatalf("Expected DMA to be done")
+	}
+	for i := 0; i < oamSize; i++ {
+		if got, _ := bus.Get(OamStart + types.Word(i)); got != 0x80 + byte(i) {
+			t.Fatalf("OAM byte %d: want 0x%02X, got 0x%02X", i, 0x80 + byte(i), got)
+		}
+	}
+}
+
+func TestDma_highSources(t *testing.T) {
+	// 0xE0-0xFF read work RAM, including the pages past echo RAM
+	for _, source := range []byte{0xE1, 0xFE, 0xFF} {
+		bus := NewBus()
+		fillDmaSource(bus, WorkRamStart + types.Word(source - 0xE0) << 8, 0x20)
+		bus.Set(DmaAddress, source)
+		tickM(bus, dmaStartDelay + oamSize)
+		for i := 0; i < oamSize; i++ {
+			if got, _ := bus.Get(OamStart + types.Word(i)); got != 0x20 + byte(i) {
+				t.Fatalf("Source 0x%02X, OAM byte %d: want 0x%02X, got 0x%02X", source, i, 0x20 + byte(i), got)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// What the console's RAM holds at power on
+type RamPattern int
+
+const (
+	// All zeroes, which is what NewBus gives
+	ZeroRam RamPattern = iota
+	// All 0xFF
+	FilledRam
+	// Roughly what a DMG powers up with: WRAM and VRAM in alternating runs of
+	// 0x00 and 0xFF with a few bits flipped, HRAM and OAM noisy
+	HardwareRam
+	// Pseudo-random bytes everywhere
+	RandomRam
+)
+
+func (p RamPattern) String() string {
+	switch p {
+	case ZeroRam:
+		return "zero"
+	case FilledRam:
+		return "filled"
+	case HardwareRam:
+		return "hardware"
+	case RandomRam:
+		return "random"
+	default:
+		return fmt.Sprintf("Unknown RAM pattern: %d", int(p))
+	}
+}
+
+const (
+	// Length of each run of 0x00 or 0xFF in the hardware pattern. The runs
+	// swap over every hardwareRowSize bytes
+	hardwareRunSize = 8
+	hardwareRowSize = 0x80
+	// One byte in this many gets a bit flipped
+	hardwareFlipChance = 16
+)
+
+// Fills WRAM, HRAM, VRAM and OAM with pattern. The same seed always gives the
+// same contents, so runs stay reproducible. Cartridge RAM is left alone, as
+// it's battery backed or belongs to the cartridge
+func (b *Bus) InitializeRam(pattern RamPattern, seed int64) {
+	random := rand.New(rand.NewSource(seed))
+	fill := func(data []byte, noisy bool) {
+		for i := range data {
+			switch {
+			case pattern == ZeroRam:
+				data[i] = 0x00
+			case pattern == FilledRam:
+				data[i] = 0xFF
+			case pattern == RandomRam || noisy:
+				data[i] = byte(random.Intn(0x100))
+			default:
+				data[i] = hardwareByte(i, random)
+			}
+		}
+	}
+	fill(b.workRam[:], false)
+	fill(b.videoRam[:], false)
+	fill(b.highRam[:], true)
+	fill(b.oam[:], true)
+}
+
+func hardwareByte(offset int, random *rand.Rand) byte {
+	value := byte(0x00)
+	if (offset / hardwareRunSize + offset / hardwareRowSize) % 2 == 1 {
+		value = 0xFF
+	}
+	if random.Intn(hardwareFlipChance) == 0 {
+		value ^= 1 << uint(random.Intn(8))
+	}
+	return value
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"testing"
+	"types"
+)
+
+// Contents of every region InitializeRam touches
+func ramContents(bus *Bus) []byte {
+	var contents []byte
+	for _, region := range []struct {
+		start types.Word
+		size int
+	}{
+		{VideoRamStart, videoRamSize},
+		{WorkRamStart, workRamSize},
+		{OamStart, oamSize},
+		{HighRamStart, highRamSize},
+	} {
+		for i := 0; i < region.size; i++ {
+			value, _ := bus.Get(region.start + types.Word(i))
+			contents = append(contents, value)
+		}
+	}
+	return contents
+}
+
+func TestInitializeRam(t *testing.T) {
+	for _, test := range []struct {
+		pattern RamPattern
+		value byte
+	}{
+		{ZeroRam, 0x00},
+		{FilledRam, 0xFF},
+	} {
+		bus := NewBus()
+		bus.InitializeRam(FilledRam, 1)
+		bus.InitializeRam(test.pattern, 1)
+		for i, value := range ramContents(bus) {
+			if value != test.value {
+				t.Fatalf("%s: byte %d is 0x%02X", test.pattern, i, value)
+			}
+		}
+	}
+}
+
+func TestInitializeRam_seeded(t *testing.T) {
+	for _, pattern := range []RamPattern{HardwareRam, RandomRam} {
+		first, second, other := NewBus(), NewBus(), NewBus()
+		first.InitializeRam(pattern, 42)
+		second.InitializeRam(pattern, 42)
+		other.InitializeRam(pattern, 43)
+		if string(ramContents(first)) != string(ramContents(second)) {
+			t.Errorf("%s: expected the same seed to give the same RAM", pattern)
+		}
+		if string(ramContents(first)) == string(ramContents(other)) {
+			t.Errorf("%s: expected different seeds to give different RAM", pattern)
+		}
+	}
+}
+
+func TestInitializeRam_hardware(t *testing.T) {
+	bus := NewBus()
+	bus.InitializeRam(HardwareRam, 7)
+	// Mostly runs of 0x00 and 0xFF, swapping over each row
+	matches := 0
+	for i := 0; i < workRamSize; i++ {
+		want := byte(0x00)
+		if (i / hardwareRunSize + i / hardwareRowSize) % 2 == 1 {
+			want = 0xFF
+		}
+		if got, _ := bus.Get(WorkRamStart + types.Word(i)); got == want {
+			matches++
+		}
+	}
+	if matches < workRamSize * 9 / 10 || matches == workRamSize {
+		t.Errorf("Expected WRAM to follow the pattern with a few flipped bits, %d of %d bytes match", matches, workRamSize)
+	}
+}
@@ -1,14 +1,11 @@
 package memory
 
 import (
+	"fmt"
 	"types"
 )
 
-const (
-	mainMemorySize = 8 * 1024
-	videoMemorySize = 8 * 1024
-)
-
+// Flat memory with no mapping, mostly for tests
 type Memory struct {
 	memory []byte
 }
@@ -17,12 +14,32 @@ func (s *Memory) Size() int {
 	return len(s.memory)
 }
 
+// Returned for an access past the end of memory
+type BusError struct {
+	Address types.Word
+	Write bool
+}
+
+func (e *BusError) Error() string {
+	access := "reading"
+	if e.Write {
+		access = "writing"
+	}
+	return fmt.Sprintf("Bus fault %s %s", access, e.Address)
+}
+
+// Reads past the end return 0xFF, like an unconnected bus
 func (s *Memory) Get(address types.Word) (byte, error) {
-	// No bounds checking is done here
+	if int(address) >= len(s.memory) {
+		return 0xFF, &BusError{Address: address}
+	}
 	return s.memory[int(address)], nil
 }
 
 func (s *Memory) Set(address types.Word, value byte) error {
+	if int(address) >= len(s.memory) {
+		return &BusError{Address: address, Write: true}
+	}
 	s.memory[int(address)] = value
 	return nil
 }
@@ -33,8 +50,7 @@ func SetupBlankMemory(size int) *Memory {
 	}
 }
 
-func InitializeMainMemory() *Memory {
-	return &Memory{
-		memory: make([]byte, mainMemorySize),
-	}
+// The console's memory map, with no cartridge inserted
+func InitializeMainMemory() *Bus {
+	return NewBus()
 }
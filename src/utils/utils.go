@@ -53,13 +53,13 @@ func Subtract8BitWithCarry(value1, value2 byte, carryBit bool) ArithmeticResults
 	if carryBit {
 		carryVal = byte(0x1)
 	}
-	sum := value1 - (value2 + carryVal)
+	sum := value1 - value2 - carryVal
 	return ArithmeticResults8Bit{
 		Result: sum,
 		Zero: sum == byte(0x0),
-		// Fairly certain these are backwards
-		HalfCarry: ((value1 & 0xF0) - ((value2 + carryVal) & 0xF0)) & 0x10 == 0x10,
-		Carry: (value2 + carryVal) > value1,
+		// Set when borrowing from bit 4 and bit 8 respectively
+		HalfCarry: int(value1 & 0xF) - int(value2 & 0xF) - int(carryVal) < 0,
+		Carry: int(value1) - int(value2) - int(carryVal) < 0,
 	}
 }
 
@@ -69,7 +69,49 @@ func Add16Bit(value1, value2 types.Word)  ArithmeticResults16Bit {
 		Result: sum,
 		Zero: sum == types.Word(0x0),
 		HalfCarry: ((value1 & 0xFFF) + (value2 & 0xFFF)) & 0x1000 == 0x1000,
-		Carry: int(value1) + int(value2) > 0xFF,
+		Carry: int(value1) + int(value2) > 0xFFFF,
 	}
 	
 }
+
+// Adds a signed 8 bit offset to a 16 bit value, as used by ADD SP,r8 and
+// LD HL,SP+r8. The carry flags come from the unsigned addition of the low
+// byte, regardless of the sign of the offset, and zero is never set.
+func AddSigned8BitOffset(value types.Word, offset byte) ArithmeticResults16Bit {
+	lowByte := byte(value & 0xFF)
+	return ArithmeticResults16Bit{
+		Result: value + types.Word(int8(offset)),
+		Zero: false,
+		HalfCarry: ((lowByte & 0xF) + (offset & 0xF)) & 0x10 == 0x10,
+		Carry: int(lowByte) + int(offset) > 0xFF,
+	}
+}
+
+// Adjusts the result of a BCD addition or subtraction so that each nibble is
+// a valid decimal digit again. Which correction is applied depends on whether
+// the last operation was a subtraction and the carries it produced.
+func DecimalAdjust(value byte, subtract, halfCarry, carry bool) ArithmeticResults8Bit {
+	result := value
+	if !subtract {
+		if carry || value > 0x99 {
+			result += 0x60
+			carry = true
+		}
+		if halfCarry || (value & 0xF) > 0x9 {
+			result += 0x06
+		}
+	} else {
+		if carry {
+			result -= 0x60
+		}
+		if halfCarry {
+			result -= 0x06
+		}
+	}
+	return ArithmeticResults8Bit{
+		Result: result,
+		Zero: result == byte(0x0),
+		HalfCarry: false,
+		Carry: carry,
+	}
+}